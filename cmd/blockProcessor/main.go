@@ -11,13 +11,12 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
 
 	"github.com/apple/foundationdb/bindings/go/src/fdb"
-	redis "github.com/go-redis/redis"
 	configs "github.com/matterinc/PlasmaBlockCreator/configs"
 	handlers "github.com/matterinc/PlasmaBlockCreator/handlers"
+	"github.com/matterinc/PlasmaBlockCreator/router"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/reuseport"
 )
@@ -31,41 +30,79 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Init foundationDB
+	eventsConfig, err := configs.ParseEventsConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
 
-	foundDB, err := configs.InitDB(databaseConfig)
+	storageConfig, err := configs.ParseStorageConfig()
 	if err != nil {
 		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
 
-	// Init redis
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     redisConfig.RedisHost + ":" + strconv.Itoa(redisConfig.RedisPort),
-		Password: redisConfig.RedisPassword,
-		DB:       0,
-	})
-	defer redisClient.Close()
+	sequencerConfig, err := configs.ParseSequencerConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
 
-	// Test for linearizability
-	fmt.Println("Testing for the counter in Redis")
-	redisCounter, err := redisClient.Get("ctr").Uint64()
+	authConfig, err := configs.ParseAuthConfig()
 	if err != nil {
-		log.Println(err)
+		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
-	fmt.Println("Redis counter = ", strconv.FormatUint(redisCounter, 10))
 
-	fmt.Println("Testing for the database")
-	maxCounterInDatabase, err := foundationdb.GetMaxTransactionCounter(foundDB)
+	operatorAuth, err := configs.InitOperatorAuth(authConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	blockSigner, err := configs.InitBlockSigner(signatureConfig, authConfig.DevMode)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	blockSchedulerConfig, err := configs.ParseBlockSchedulerConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	blockArchiveConfig, err := configs.ParseBlockArchiveConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	// Init storage
+
+	foundDB, err := configs.InitStorage(storageConfig, databaseConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	// Init sequencer
+	seq, err := configs.InitSequencer(sequencerConfig, redisConfig, foundDB)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	defer seq.Close()
+
+	// Test for linearizability
+	err = foundationdb.CheckSequencer(foundDB, seq)
 	if err != nil {
 		log.Println(err)
 		os.Exit(1)
 	}
-	fmt.Println("Database counter = ", strconv.FormatUint(maxCounterInDatabase, 10))
 
-	if maxCounterInDatabase > redisCounter {
-		log.Println("Counters mismatch")
+	err = configs.InitLastBlockHash(blockArchiveConfig, foundDB)
+	if err != nil {
+		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
 
@@ -86,21 +123,37 @@ func main() {
 	fmt.Println("ECRecover concurrency = " + strconv.Itoa(ECRecoverConcurrency))
 	fmt.Println("FDB concurrency = " + strconv.Itoa(DatabaseConcurrency))
 
-	assembleBlockHandler := handlers.NewAssembleBlockHandler(foundDB, redisClient, common.FromHex(signatureConfig.BlockSigningKey))
-	writeBlockHandler := handlers.NewWriteBlockHandler(foundDB)
+	publisher, _, err := configs.InitEvents(eventsConfig, redisConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	assembleBlockHandler := handlers.NewAssembleBlockHandler(foundDB, seq, blockSigner)
+	previewBlockHandler := handlers.NewPreviewBlockHandler(foundDB, seq)
+	writeBlockHandler := handlers.NewWriteBlockHandler(foundDB, publisher)
 	lastBlockHandler := handlers.NewLastBlockHandler(foundDB)
-	m := func(ctx *fasthttp.RequestCtx) {
-		switch string(ctx.Path()) {
-		case "/assembleBlock":
-			assembleBlockHandler.HandlerFunc(ctx)
-		case "/lastWrittenBlock":
-			lastBlockHandler.HandlerFunc(ctx)
-		case "/writeBlock":
-			writeBlockHandler.HandlerFunc(ctx)
-		default:
-			ctx.Error("Not found", fasthttp.StatusNotFound)
-		}
+	scheduler := handlers.NewBlockScheduler(foundDB, assembleBlockHandler, writeBlockHandler,
+		blockSchedulerConfig.Interval, blockSchedulerConfig.Threshold)
+	middleware := []router.Middleware{}
+	if httpConfig.LogRequests {
+		middleware = append(middleware, router.Logging())
 	}
+	middleware = append(middleware, router.CORS())
+	validate, err := configs.InitValidation(httpConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	r := router.New(middleware...)
+	routes := handlers.Routes{PreviewBlock: previewBlockHandler,
+		LastBlock: lastBlockHandler}
+	// blocks from outside would race the scheduler for the same block number
+	if scheduler == nil {
+		routes.AssembleBlock = assembleBlockHandler
+		routes.WriteBlock = writeBlockHandler
+	}
+	routes.Register(r, validate, operatorAuth)
 
 	server := fasthttp.Server{
 		Name:               "PlasmaUTXOlister",
@@ -108,7 +161,7 @@ func main() {
 		MaxConnsPerIP:      httpConfig.MaxConnectionsPerIP,
 		WriteTimeout:       time.Second * 15,
 		ReadTimeout:        time.Second * 15,
-		Handler:            m,
+		Handler:            r.Handler(),
 		MaxRequestBodySize: httpConfig.MaxBodySize,
 	}
 
@@ -124,6 +177,10 @@ func main() {
 	}()
 
 	fmt.Println("Started to listen on " + "0.0.0.0" + ":" + strconv.Itoa(httpConfig.Port))
+	if scheduler != nil {
+		scheduler.Start()
+		fmt.Println("Started the block scheduler")
+	}
 	wait := time.Second * 15
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
@@ -131,6 +188,9 @@ func main() {
 	_, cancel := context.WithTimeout(context.Background(), wait)
 	defer cancel()
 	listener.Close()
+	if scheduler != nil {
+		scheduler.Stop()
+	}
 	log.Println("Shutting down")
 	os.Exit(0)
 }
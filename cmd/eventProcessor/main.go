@@ -11,13 +11,12 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
 
 	"github.com/apple/foundationdb/bindings/go/src/fdb"
-	redis "github.com/go-redis/redis"
 	configs "github.com/matterinc/PlasmaBlockCreator/configs"
 	handlers "github.com/matterinc/PlasmaBlockCreator/handlers"
+	"github.com/matterinc/PlasmaBlockCreator/router"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/reuseport"
 )
@@ -31,41 +30,61 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Init foundationDB
+	eventsConfig, err := configs.ParseEventsConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
 
-	foundDB, err := configs.InitDB(databaseConfig)
+	storageConfig, err := configs.ParseStorageConfig()
 	if err != nil {
 		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
 
-	// Init redis
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     redisConfig.RedisHost + ":" + strconv.Itoa(redisConfig.RedisPort),
-		Password: redisConfig.RedisPassword,
-		DB:       0,
-	})
-	defer redisClient.Close()
+	sequencerConfig, err := configs.ParseSequencerConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
 
-	// Test for linearizability
-	fmt.Println("Testing for the counter in Redis")
-	redisCounter, err := redisClient.Get("ctr").Uint64()
+	authConfig, err := configs.ParseAuthConfig()
 	if err != nil {
-		log.Println(err)
+		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
-	fmt.Println("Redis counter = ", strconv.FormatUint(redisCounter, 10))
 
-	fmt.Println("Testing for the database")
-	maxCounterInDatabase, err := foundationdb.GetMaxTransactionCounter(foundDB)
+	operatorAuth, err := configs.InitOperatorAuth(authConfig)
 	if err != nil {
-		log.Println(err)
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	fundingTXSigner, err := configs.InitFundingTXSigner(signatureConfig, authConfig.DevMode)
+	if err != nil {
+		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
-	fmt.Println("Database counter = ", strconv.FormatUint(maxCounterInDatabase, 10))
 
-	if maxCounterInDatabase > redisCounter {
-		log.Println("Counters mismatch")
+	// Init storage
+
+	foundDB, err := configs.InitStorage(storageConfig, databaseConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	// Init sequencer
+	seq, err := configs.InitSequencer(sequencerConfig, redisConfig, foundDB)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	defer seq.Close()
+
+	// Test for linearizability
+	err = foundationdb.CheckSequencer(foundDB, seq)
+	if err != nil {
+		log.Println(err)
 		os.Exit(1)
 	}
 
@@ -86,21 +105,30 @@ func main() {
 	fmt.Println("ECRecover concurrency = " + strconv.Itoa(ECRecoverConcurrency))
 	fmt.Println("FDB concurrency = " + strconv.Itoa(DatabaseConcurrency))
 
-	createFundingTXhandler := handlers.NewCreateFundingTXHandler(foundDB, redisClient, common.FromHex(signatureConfig.FundingTXSigningKey))
-	processNormalExitHandler := handlers.NewWithdrawTXHandler(foundDB)
+	publisher, _, err := configs.InitEvents(eventsConfig, redisConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	createFundingTXhandler := handlers.NewCreateFundingTXHandler(foundDB, seq, fundingTXSigner)
+	processNormalExitHandler := handlers.NewWithdrawTXHandler(foundDB, publisher)
 	processDepositExitHandler := handlers.NewDepositWithdrawTXHandler(foundDB)
-	m := func(ctx *fasthttp.RequestCtx) {
-		switch string(ctx.Path()) {
-		case "/processEvent/DepositEvent":
-			createFundingTXhandler.HandlerFunc(ctx)
-		case "/processEvent/ExitStartedEvent":
-			processNormalExitHandler.HandlerFunc(ctx)
-		case "/processEvent/DepositWithdrawStartedEvent":
-			processDepositExitHandler.HandlerFunc(ctx)
-		default:
-			ctx.Error("Not found", fasthttp.StatusNotFound)
-		}
+	middleware := []router.Middleware{}
+	if httpConfig.LogRequests {
+		middleware = append(middleware, router.Logging())
+	}
+	middleware = append(middleware, router.CORS())
+	validate, err := configs.InitValidation(httpConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
 	}
+	r := router.New(middleware...)
+	routes := handlers.Routes{Deposit: createFundingTXhandler,
+		Exit:        processNormalExitHandler,
+		DepositExit: processDepositExitHandler}
+	routes.Register(r, validate, operatorAuth)
 
 	server := fasthttp.Server{
 		Name:               "PlasmaUTXOlister",
@@ -108,7 +136,7 @@ func main() {
 		MaxConnsPerIP:      httpConfig.MaxConnectionsPerIP,
 		WriteTimeout:       time.Second * 15,
 		ReadTimeout:        time.Second * 15,
-		Handler:            m,
+		Handler:            r.Handler(),
 		MaxRequestBodySize: httpConfig.MaxBodySize,
 	}
 
@@ -11,14 +11,13 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
 	"github.com/matterinc/PlasmaCommons/transaction"
 
 	"github.com/apple/foundationdb/bindings/go/src/fdb"
-	redis "github.com/go-redis/redis"
 	configs "github.com/matterinc/PlasmaBlockCreator/configs"
 	handlers "github.com/matterinc/PlasmaBlockCreator/handlers"
+	"github.com/matterinc/PlasmaBlockCreator/router"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/reuseport"
 )
@@ -32,41 +31,90 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Init foundationDB
+	eventsConfig, err := configs.ParseEventsConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
 
-	foundDB, err := configs.InitDB(databaseConfig)
+	rejectionConfig, err := configs.ParseRejectionConfig()
 	if err != nil {
 		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
 
-	// Init redis
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     redisConfig.RedisHost + ":" + strconv.Itoa(redisConfig.RedisPort),
-		Password: redisConfig.RedisPassword,
-		DB:       0,
-	})
-	defer redisClient.Close()
+	storageConfig, err := configs.ParseStorageConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
 
-	// Test for linearizability
-	fmt.Println("Testing for the counter in Redis")
-	redisCounter, err := redisClient.Get("ctr").Uint64()
+	sequencerConfig, err := configs.ParseSequencerConfig()
 	if err != nil {
-		log.Println(err)
+		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
-	fmt.Println("Redis counter = ", strconv.FormatUint(redisCounter, 10))
 
-	fmt.Println("Testing for the database")
-	maxCounterInDatabase, err := foundationdb.GetMaxTransactionCounter(foundDB)
+	authConfig, err := configs.ParseAuthConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	operatorAuth, err := configs.InitOperatorAuth(authConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	blockSigner, err := configs.InitBlockSigner(signatureConfig, authConfig.DevMode)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	fundingTXSigner, err := configs.InitFundingTXSigner(signatureConfig, authConfig.DevMode)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	rateLimitConfig, err := configs.ParseRateLimitConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	blockArchiveConfig, err := configs.ParseBlockArchiveConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	// Init storage
+
+	foundDB, err := configs.InitStorage(storageConfig, databaseConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	// Init sequencer
+	seq, err := configs.InitSequencer(sequencerConfig, redisConfig, foundDB)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	defer seq.Close()
+
+	// Test for linearizability
+	err = foundationdb.CheckSequencer(foundDB, seq)
 	if err != nil {
 		log.Println(err)
 		os.Exit(1)
 	}
-	fmt.Println("Database counter = ", strconv.FormatUint(maxCounterInDatabase, 10))
 
-	if maxCounterInDatabase > redisCounter {
-		log.Println("Counters mismatch")
+	err = configs.InitLastBlockHash(blockArchiveConfig, foundDB)
+	if err != nil {
+		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
 
@@ -88,41 +136,80 @@ func main() {
 	fmt.Println("FDB concurrency = " + strconv.Itoa(DatabaseConcurrency))
 
 	transactionParser := transaction.NewTransactionParser(ECRecoverConcurrency)
-	sendRawTXHandler := handlers.NewSendRawTXHandler(foundDB, redisClient, transactionParser, DatabaseConcurrency)
-	createUTXOHandler := handlers.NewCreateUTXOHandler(foundDB)
+	publisher, broker, err := configs.InitEvents(eventsConfig, redisConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	rejected, err := configs.InitRejections(rejectionConfig, redisConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	sendRawTXHandler := handlers.NewSendRawTXHandler(foundDB, seq, transactionParser, DatabaseConcurrency, publisher, rejected)
+	sendRawTXHandler.SetRateLimits(configs.InitRateLimits(rateLimitConfig))
+	sendRawTXsHandler := handlers.NewSendRawTXsHandler(sendRawTXHandler)
 	listUTXOsHandler := handlers.NewListUTXOsHandler(foundDB)
-	assembleBlockHandler := handlers.NewAssembleBlockHandler(foundDB, redisClient, common.FromHex(signatureConfig.BlockSigningKey))
-	createFundingTXhandler := handlers.NewCreateFundingTXHandler(foundDB, redisClient, common.FromHex(signatureConfig.FundingTXSigningKey))
-	writeBlockHandler := handlers.NewWriteBlockHandler(foundDB)
+	getTransactionHandler := handlers.NewGetTransactionHandler(foundDB, rejected)
+	getTransactionStatusHandler := handlers.NewGetTransactionStatusHandler(foundDB, rejected)
+	getBlockHandler := handlers.NewGetBlockHandler(foundDB)
+	getBlockHeaderHandler := handlers.NewGetBlockHeaderHandler(foundDB)
+	getProofHandler := handlers.NewGetProofHandler(foundDB, rejected)
+	getBalanceHandler := handlers.NewGetBalanceHandler(foundDB)
+	subscribeHandler := handlers.NewSubscribeHandler(broker)
+	jsonRPCHandler := handlers.NewJSONRPCHandler()
+	jsonRPCHandler.RegisterSendMethods(sendRawTXHandler)
+	jsonRPCHandler.RegisterReadMethods(foundDB, rejected)
+	assembleBlockHandler := handlers.NewAssembleBlockHandler(foundDB, seq, blockSigner)
+	previewBlockHandler := handlers.NewPreviewBlockHandler(foundDB, seq)
+	createFundingTXhandler := handlers.NewCreateFundingTXHandler(foundDB, seq, fundingTXSigner)
+	writeBlockHandler := handlers.NewWriteBlockHandler(foundDB, publisher)
 	lastBlockHandler := handlers.NewLastBlockHandler(foundDB)
-	processNormalExitHandler := handlers.NewWithdrawTXHandler(foundDB)
+	processNormalExitHandler := handlers.NewWithdrawTXHandler(foundDB, publisher)
 	processDepositExitHandler := handlers.NewDepositWithdrawTXHandler(foundDB)
-	m := func(ctx *fasthttp.RequestCtx) {
-		switch string(ctx.Path()) {
-		case "/sendRawTX":
-			sendRawTXHandler.HandlerFunc(ctx)
-		case "/createUTXO":
-			createUTXOHandler.HandlerFunc(ctx) // debug only
-		case "/listUTXOs":
-			listUTXOsHandler.HandlerFunc(ctx)
-		case "/assembleBlock":
-			assembleBlockHandler.HandlerFunc(ctx)
-		case "/createFundingTX":
-			createFundingTXhandler.HandlerFunc(ctx) // legacy
-		case "/lastWrittenBlock":
-			lastBlockHandler.HandlerFunc(ctx)
-		case "/writeBlock":
-			writeBlockHandler.HandlerFunc(ctx)
-		case "/processEvent/DepositEvent":
-			createFundingTXhandler.HandlerFunc(ctx)
-		case "/processEvent/ExitStartedEvent":
-			processNormalExitHandler.HandlerFunc(ctx)
-		case "/processEvent/DepositWithdrawStartedEvent":
-			processDepositExitHandler.HandlerFunc(ctx)
-		default:
-			ctx.Error("Not found", fasthttp.StatusNotFound)
-		}
+	middleware := []router.Middleware{}
+	if httpConfig.LogRequests {
+		middleware = append(middleware, router.Logging())
+	}
+	middleware = append(middleware, router.CORS())
+	realIP, err := configs.InitTrustedProxies(httpConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	if realIP != nil {
+		middleware = append(middleware, realIP)
+	}
+	validate, err := configs.InitValidation(httpConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	r := router.New(middleware...)
+	routes := handlers.Routes{SendRawTX: sendRawTXHandler,
+		SendRawTXs:           sendRawTXsHandler,
+		ListUTXOs:            listUTXOsHandler,
+		GetTransaction:       getTransactionHandler,
+		GetTransactionStatus: getTransactionStatusHandler,
+		GetBlock:             getBlockHandler,
+		GetBlockHeader:       getBlockHeaderHandler,
+		GetProof:             getProofHandler,
+		GetBalance:           getBalanceHandler,
+		Subscribe:            subscribeHandler,
+		JSONRPC:              jsonRPCHandler,
+		AssembleBlock:        assembleBlockHandler,
+		PreviewBlock:         previewBlockHandler,
+		LegacyFundingTX:      createFundingTXhandler,
+		LastBlock:            lastBlockHandler,
+		WriteBlock:           writeBlockHandler,
+		Deposit:              createFundingTXhandler,
+		Exit:                 processNormalExitHandler,
+		DepositExit:          processDepositExitHandler}
+	if authConfig.DevMode {
+		routes.CreateUTXO = handlers.NewCreateUTXOHandler(foundDB)
 	}
+	routes.Register(r, validate, operatorAuth)
 
 	server := fasthttp.Server{
 		Name:               "PlasmaUTXOlister",
@@ -130,7 +217,7 @@ func main() {
 		MaxConnsPerIP:      httpConfig.MaxConnectionsPerIP,
 		WriteTimeout:       time.Second * 15,
 		ReadTimeout:        time.Second * 15,
-		Handler:            m,
+		Handler:            r.Handler(),
 		MaxRequestBodySize: httpConfig.MaxBodySize,
 	}
 
@@ -13,57 +13,70 @@ import (
 	"github.com/apple/foundationdb/bindings/go/src/fdb"
 	configs "github.com/matterinc/PlasmaBlockCreator/configs"
 	handlers "github.com/matterinc/PlasmaBlockCreator/handlers"
+	"github.com/matterinc/PlasmaBlockCreator/plasmapb"
+	"github.com/matterinc/PlasmaBlockCreator/ratelimit"
+	"github.com/matterinc/PlasmaBlockCreator/router"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/reuseport"
+	"google.golang.org/grpc"
 )
 
 func main() {
 	fdb.MustAPIVersion(520)
 
-	httpConfig, _, _, databaseConfig, _, err := configs.ParseConfigs()
+	httpConfig, redisConfig, _, databaseConfig, _, err := configs.ParseConfigs()
 	// httpConfig, redisConfig, concurrencyConfig, databaseConfig, _, err := configs.ParseConfigs()
 	if err != nil {
 		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
 
-	// Init foundationDB
+	grpcConfig, err := configs.ParseGRPCConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
 
-	foundDB, err := configs.InitDB(databaseConfig)
+	eventsConfig, err := configs.ParseEventsConfig()
 	if err != nil {
 		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
 
-	// // Init redis
-	// redisClient := redis.NewClient(&redis.Options{
-	// 	Addr:     redisConfig.RedisHost + ":" + strconv.Itoa(redisConfig.RedisPort),
-	// 	Password: redisConfig.RedisPassword,
-	// 	DB:       0,
-	// })
-	// defer redisClient.Close()
+	rejectionConfig, err := configs.ParseRejectionConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
 
-	// // Test for linearizability
-	// fmt.Println("Testing for the counter in Redis")
-	// redisCounter, err := redisClient.Get("ctr").Uint64()
+	storageConfig, err := configs.ParseStorageConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	// Init storage
+
+	foundDB, err := configs.InitStorage(storageConfig, databaseConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
+	// // Init sequencer
+	// seq, err := configs.InitSequencer(sequencerConfig, redisConfig, foundDB)
 	// if err != nil {
-	// 	log.Println(err)
+	// 	log.Printf("%+v\n", err)
 	// 	os.Exit(1)
 	// }
-	// fmt.Println("Redis counter = ", strconv.FormatUint(redisCounter, 10))
+	// defer seq.Close()
 
-	// fmt.Println("Testing for the database")
-	// maxCounterInDatabase, err := foundationdb.GetMaxTransactionCounter(foundDB)
+	// // Test for linearizability
+	// err = foundationdb.CheckSequencer(foundDB, seq)
 	// if err != nil {
 	// 	log.Println(err)
 	// 	os.Exit(1)
 	// }
-	// fmt.Println("Database counter = ", strconv.FormatUint(maxCounterInDatabase, 10))
-
-	// if maxCounterInDatabase > redisCounter {
-	// 	log.Println("Counters mismatch")
-	// 	os.Exit(1)
-	// }
 
 	// ECRecoverConcurrency := concurrencyConfig.ECRecoverConcurrency
 	// if ECRecoverConcurrency == -1 {
@@ -82,15 +95,51 @@ func main() {
 	// fmt.Println("ECRecover concurrency = " + strconv.Itoa(ECRecoverConcurrency))
 	// fmt.Println("FDB concurrency = " + strconv.Itoa(DatabaseConcurrency))
 
+	_, broker, err := configs.InitEvents(eventsConfig, redisConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	rejected, err := configs.InitRejections(rejectionConfig, redisConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
 	listUTXOsHandler := handlers.NewListUTXOsHandler(foundDB)
-	m := func(ctx *fasthttp.RequestCtx) {
-		switch string(ctx.Path()) {
-		case "/listUTXOs":
-			listUTXOsHandler.HandlerFunc(ctx)
-		default:
-			ctx.Error("Not found", fasthttp.StatusNotFound)
-		}
+	getTransactionHandler := handlers.NewGetTransactionHandler(foundDB, rejected)
+	getTransactionStatusHandler := handlers.NewGetTransactionStatusHandler(foundDB, rejected)
+	getBlockHandler := handlers.NewGetBlockHandler(foundDB)
+	getBlockHeaderHandler := handlers.NewGetBlockHeaderHandler(foundDB)
+	getProofHandler := handlers.NewGetProofHandler(foundDB, rejected)
+	getBalanceHandler := handlers.NewGetBalanceHandler(foundDB)
+	subscribeHandler := handlers.NewSubscribeHandler(broker)
+	jsonRPCHandler := handlers.NewJSONRPCHandler()
+	jsonRPCHandler.RegisterReadMethods(foundDB, rejected)
+	middleware := []router.Middleware{}
+	if httpConfig.LogRequests {
+		middleware = append(middleware, router.Logging())
 	}
+	middleware = append(middleware, router.CORS())
+	// HTTP and gRPC calls share one limit, the servers only cap their own connections
+	concurrency := ratelimit.NewConcurrency(httpConfig.HTTPConcurrency)
+	middleware = append(middleware, router.Limit(concurrency))
+	validate, err := configs.InitValidation(httpConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	r := router.New(middleware...)
+	routes := handlers.Routes{ListUTXOs: listUTXOsHandler,
+		GetTransaction:       getTransactionHandler,
+		GetTransactionStatus: getTransactionStatusHandler,
+		GetBlock:             getBlockHandler,
+		GetBlockHeader:       getBlockHeaderHandler,
+		GetProof:             getProofHandler,
+		GetBalance:           getBalanceHandler,
+		Subscribe:            subscribeHandler,
+		JSONRPC:              jsonRPCHandler}
+	routes.Register(r, validate, nil)
 
 	server := fasthttp.Server{
 		Name:               "PlasmaUTXOlister",
@@ -98,7 +147,7 @@ func main() {
 		MaxConnsPerIP:      httpConfig.MaxConnectionsPerIP,
 		WriteTimeout:       time.Second * 15,
 		ReadTimeout:        time.Second * 15,
-		Handler:            m,
+		Handler:            r.Handler(),
 		MaxRequestBodySize: httpConfig.MaxBodySize,
 	}
 
@@ -114,6 +163,30 @@ func main() {
 	}()
 
 	fmt.Println("Started to listen on " + "0.0.0.0" + ":" + strconv.Itoa(httpConfig.Port))
+
+	var grpcServer *grpc.Server
+	if grpcConfig.Port != 0 {
+		grpcListener, err := net.Listen("tcp4", "0.0.0.0"+":"+strconv.Itoa(grpcConfig.Port))
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		plasmaServer := handlers.NewGRPCServer()
+		plasmaServer.RegisterReadMethods(foundDB)
+		unaryLimit, streamLimit := handlers.GRPCLimit(concurrency)
+		grpcServer = grpc.NewServer(grpc.MaxConcurrentStreams(uint32(httpConfig.HTTPConcurrency)),
+			grpc.MaxRecvMsgSize(httpConfig.MaxBodySize),
+			grpc.UnaryInterceptor(unaryLimit),
+			grpc.StreamInterceptor(streamLimit))
+		plasmapb.RegisterPlasmaServer(grpcServer, plasmaServer)
+		go func() {
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				log.Println(err)
+			}
+		}()
+		fmt.Println("Started gRPC on " + "0.0.0.0" + ":" + strconv.Itoa(grpcConfig.Port))
+	}
+
 	wait := time.Second * 15
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
@@ -121,6 +194,9 @@ func main() {
 	_, cancel := context.WithTimeout(context.Background(), wait)
 	defer cancel()
 	listener.Close()
+	if grpcServer != nil {
+		grpcServer.Stop()
+	}
 	log.Println("Shutting down")
 	os.Exit(0)
 }
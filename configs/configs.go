@@ -4,16 +4,42 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"time"
 
 	"github.com/apple/foundationdb/bindings/go/src/fdb"
 	"github.com/caarlos0/env"
+	common "github.com/ethereum/go-ethereum/common"
+	redis "github.com/go-redis/redis"
+	"github.com/matterinc/PlasmaBlockCreator/events"
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/openapi"
+	"github.com/matterinc/PlasmaBlockCreator/ratelimit"
+	"github.com/matterinc/PlasmaBlockCreator/rejections"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/matterinc/PlasmaBlockCreator/sequencer"
+	"github.com/matterinc/PlasmaBlockCreator/signer"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/matterinc/PlasmaBlockCreator/storage/boltstorage"
+	"github.com/matterinc/PlasmaBlockCreator/storage/fdbstorage"
 )
 
 type HTTPConfig struct {
-	Port                int `env:"PORT" envDefault:"3001"`
-	HTTPConcurrency     int `env:"HTTP_CONCURRENCY" envDefault:"50000"`
-	MaxConnectionsPerIP int `env:"HTTP_MAXCONNECTIONS" envDefault:"50000"`
-	MaxBodySize         int `env:"HTTP_MAXBODYSIZE" envDefault:"5000"`
+	Port                int  `env:"PORT" envDefault:"3001"`
+	HTTPConcurrency     int  `env:"HTTP_CONCURRENCY" envDefault:"50000"`
+	MaxConnectionsPerIP int  `env:"HTTP_MAXCONNECTIONS" envDefault:"50000"`
+	MaxBodySize         int  `env:"HTTP_MAXBODYSIZE" envDefault:"5000"`
+	LogRequests         bool `env:"HTTP_LOG_REQUESTS" envDefault:"false"`
+	// OpenAPISpec is checked against JSON request bodies, empty disables the checks
+	OpenAPISpec string `env:"HTTP_OPENAPI_SPEC" envDefault:"docs/plasma.yaml"`
+	// TrustedProxies are IPs or CIDR ranges whose X-Forwarded-For and X-Real-IP headers
+	// are used for client limits, empty uses the address of the connection
+	TrustedProxies []string `env:"HTTP_TRUSTED_PROXIES" envSeparator:","`
+}
+
+// GRPCConfig enables the gRPC service on a separate port, zero keeps it disabled
+type GRPCConfig struct {
+	Port int `env:"GRPC_PORT" envDefault:"0"`
 }
 
 type RedisConfig struct {
@@ -33,9 +59,80 @@ type FDBConfig struct {
 	FdbClusterFilePath    string `env:"FDB_CLUSTER_FILE_PATH" envDefault:""`
 }
 
+// StorageConfig selects a storage backend, "foundationdb" for a cluster or
+// "embedded" for a single file on a local disk
+type StorageConfig struct {
+	Backend      string `env:"STORAGE_BACKEND" envDefault:"foundationdb"`
+	EmbeddedPath string `env:"EMBEDDED_DB_PATH" envDefault:"./plasma.db"`
+}
+
+// SequencerConfig selects where transaction counters come from, "redis" for a shared
+// counter, "local" for a counter kept in the storage itself or "versionstamp" to order
+// transactions by storage commit versionstamps without any counter. A block is closed
+// at MaxBlockTransactions or MaxBlockBytes of encoded transactions, zero is unlimited
+type SequencerConfig struct {
+	Backend              string `env:"SEQUENCER" envDefault:"redis"`
+	MaxBlockTransactions int    `env:"BLOCK_MAX_TRANSACTIONS" envDefault:"0"`
+	MaxBlockBytes        int    `env:"BLOCK_MAX_BYTES" envDefault:"0"`
+}
+
+// EventsConfig selects how events reach WebSocket subscribers, "none" to drop them,
+// "local" when producers and subscribers share a process or "redis" for pub/sub between binaries
+type EventsConfig struct {
+	Backend string `env:"EVENTS" envDefault:"none"`
+}
+
+// RejectionConfig selects where rejected transactions are remembered for their status,
+// "memory" within a process or "redis" between the binaries that accept and report them
+type RejectionConfig struct {
+	Backend  string        `env:"REJECTIONS" envDefault:"memory"`
+	TTL      time.Duration `env:"REJECTION_TTL" envDefault:"1h"`
+	Capacity int           `env:"REJECTION_CAPACITY" envDefault:"100000"`
+}
+
+// AuthConfig protects operator routes with any of the comma separated API keys or with
+// requests signed by the HMAC secret. DevMode allows them without credentials and adds debug routes
+type AuthConfig struct {
+	APIKeys    []string `env:"OPERATOR_API_KEYS" envSeparator:","`
+	HMACSecret string   `env:"OPERATOR_HMAC_SECRET" envDefault:""`
+	DevMode    bool     `env:"DEV_MODE" envDefault:"false"`
+}
+
+// RateLimitConfig limits transaction submissions to a rate per second with bursts of up to
+// burst transactions, per client IP and per sender address. A zero rate disables a limit
+type RateLimitConfig struct {
+	IPRate      float64 `env:"RATELIMIT_IP_RATE" envDefault:"0"`
+	IPBurst     int     `env:"RATELIMIT_IP_BURST" envDefault:"0"`
+	SenderRate  float64 `env:"RATELIMIT_SENDER_RATE" envDefault:"0"`
+	SenderBurst int     `env:"RATELIMIT_SENDER_BURST" envDefault:"0"`
+}
+
+// BlockSchedulerConfig lets the block processor produce blocks on its own, every interval
+// (e.g. "30s") or once threshold transactions are pending. Zero values leave blocks to /assembleBlock
+type BlockSchedulerConfig struct {
+	Interval  time.Duration `env:"BLOCK_INTERVAL" envDefault:"0s"`
+	Threshold int           `env:"BLOCK_PENDING_THRESHOLD" envDefault:"0"`
+}
+
+// BlockArchiveConfig takes the hash of the last written block for deployments that wrote
+// blocks before they were archived, the next block needs it as the parent hash
+type BlockArchiveConfig struct {
+	ParentHash string `env:"BLOCK_PARENT_HASH" envDefault:""`
+}
+
+// SignatureConfig selects how blocks and funding transactions are signed: "key" with the hex
+// keys, "keystore" with encrypted keystore files or "remote" by a signing service.
+// The default key is refused unless DEV_MODE is on
 type SignatureConfig struct {
-	FundingTXSigningKey string `env:"FUNDINGTX_ETH_KEY" envDefault:"0xc87509a1c067bbde78beb793e6fa76530b6382a4c0241e5e4a9ec0a0f44dc0d3"`
-	BlockSigningKey     string `env:"BLOCK_ETH_KEY" envDefault:"0xc87509a1c067bbde78beb793e6fa76530b6382a4c0241e5e4a9ec0a0f44dc0d3"`
+	Signer              string        `env:"SIGNER" envDefault:"key"`
+	FundingTXSigningKey string        `env:"FUNDINGTX_ETH_KEY" envDefault:"0xc87509a1c067bbde78beb793e6fa76530b6382a4c0241e5e4a9ec0a0f44dc0d3"`
+	BlockSigningKey     string        `env:"BLOCK_ETH_KEY" envDefault:"0xc87509a1c067bbde78beb793e6fa76530b6382a4c0241e5e4a9ec0a0f44dc0d3"`
+	FundingTXKeystore   string        `env:"FUNDINGTX_KEYSTORE" envDefault:""`
+	BlockKeystore       string        `env:"BLOCK_KEYSTORE" envDefault:""`
+	KeystorePassword    string        `env:"KEYSTORE_PASSWORD" envDefault:""`
+	FundingTXSignerURL  string        `env:"FUNDINGTX_SIGNER_URL" envDefault:""`
+	BlockSignerURL      string        `env:"BLOCK_SIGNER_URL" envDefault:""`
+	SignerTimeout       time.Duration `env:"SIGNER_TIMEOUT" envDefault:"5s"`
 }
 
 func ParseConfigs() (*HTTPConfig, *RedisConfig, *ConcurrencyConfig, *FDBConfig, *SignatureConfig, error) {
@@ -71,26 +168,321 @@ func ParseConfigs() (*HTTPConfig, *RedisConfig, *ConcurrencyConfig, *FDBConfig,
 	}
 	fmt.Printf("%+v\n", databaseConfig)
 
+	signatureConfig, err := ParseSignatureConfig()
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	return &httpConfig, &redisConfig, &concurrencyConfig, &databaseConfig, signatureConfig, nil
+
+}
+
+func ParseStorageConfig() (*StorageConfig, error) {
+	storageConfig := StorageConfig{}
+	err := env.Parse(&storageConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		return nil, err
+	}
+	fmt.Printf("%+v\n", storageConfig)
+	return &storageConfig, nil
+}
+
+func ParseGRPCConfig() (*GRPCConfig, error) {
+	grpcConfig := GRPCConfig{}
+	err := env.Parse(&grpcConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		return nil, err
+	}
+	fmt.Printf("%+v\n", grpcConfig)
+	return &grpcConfig, nil
+}
+
+func ParseSequencerConfig() (*SequencerConfig, error) {
+	sequencerConfig := SequencerConfig{}
+	err := env.Parse(&sequencerConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		return nil, err
+	}
+	fmt.Printf("%+v\n", sequencerConfig)
+	return &sequencerConfig, nil
+}
+
+func ParseEventsConfig() (*EventsConfig, error) {
+	eventsConfig := EventsConfig{}
+	err := env.Parse(&eventsConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		return nil, err
+	}
+	fmt.Printf("%+v\n", eventsConfig)
+	return &eventsConfig, nil
+}
+
+func ParseRejectionConfig() (*RejectionConfig, error) {
+	rejectionConfig := RejectionConfig{}
+	err := env.Parse(&rejectionConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		return nil, err
+	}
+	fmt.Printf("%+v\n", rejectionConfig)
+	return &rejectionConfig, nil
+}
+
+// ParseAuthConfig does not print the config, it holds secrets
+func ParseAuthConfig() (*AuthConfig, error) {
+	authConfig := AuthConfig{}
+	err := env.Parse(&authConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		return nil, err
+	}
+	fmt.Println("Operator API keys = " + strconv.Itoa(len(authConfig.APIKeys)) +
+		", HMAC secret set = " + strconv.FormatBool(authConfig.HMACSecret != "") +
+		", dev mode = " + strconv.FormatBool(authConfig.DevMode))
+	return &authConfig, nil
+}
+
+// ParseSignatureConfig does not print the config, it holds keys
+func ParseSignatureConfig() (*SignatureConfig, error) {
 	signatureConfig := SignatureConfig{}
-	err = env.Parse(&signatureConfig)
+	err := env.Parse(&signatureConfig)
 	if err != nil {
 		log.Printf("%+v\n", err)
-		return nil, nil, nil, nil, nil, err
+		return nil, err
 	}
-	fmt.Printf("%+v\n", signatureConfig)
+	fmt.Println("Signer = " + signatureConfig.Signer)
+	return &signatureConfig, nil
+}
 
-	return &httpConfig, &redisConfig, &concurrencyConfig, &databaseConfig, &signatureConfig, nil
+func ParseRateLimitConfig() (*RateLimitConfig, error) {
+	rateLimitConfig := RateLimitConfig{}
+	err := env.Parse(&rateLimitConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		return nil, err
+	}
+	fmt.Printf("%+v\n", rateLimitConfig)
+	return &rateLimitConfig, nil
+}
+
+func ParseBlockSchedulerConfig() (*BlockSchedulerConfig, error) {
+	blockSchedulerConfig := BlockSchedulerConfig{}
+	err := env.Parse(&blockSchedulerConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		return nil, err
+	}
+	fmt.Printf("%+v\n", blockSchedulerConfig)
+	return &blockSchedulerConfig, nil
+}
+
+func ParseBlockArchiveConfig() (*BlockArchiveConfig, error) {
+	blockArchiveConfig := BlockArchiveConfig{}
+	err := env.Parse(&blockArchiveConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		return nil, err
+	}
+	fmt.Printf("%+v\n", blockArchiveConfig)
+	return &blockArchiveConfig, nil
+}
+
+// InitRateLimits returns the limiters per client IP and per sender, nil for a disabled one
+func InitRateLimits(rateLimitConfig *RateLimitConfig) (*ratelimit.Limiter, *ratelimit.Limiter) {
+	return ratelimit.NewLimiter(rateLimitConfig.IPRate, rateLimitConfig.IPBurst),
+		ratelimit.NewLimiter(rateLimitConfig.SenderRate, rateLimitConfig.SenderBurst)
+}
+
+func newRedisClient(redisConfig *RedisConfig) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     redisConfig.RedisHost + ":" + strconv.Itoa(redisConfig.RedisPort),
+		Password: redisConfig.RedisPassword,
+		DB:       0,
+	})
+}
+
+// InitEvents returns where the binary publishes events and the broker its own
+// subscribers are served from
+func InitEvents(eventsConfig *EventsConfig, redisConfig *RedisConfig) (events.Publisher, *events.Broker, error) {
+	broker := events.NewBroker()
+	switch eventsConfig.Backend {
+	case "none":
+		return events.Discard, broker, nil
+	case "local":
+		return broker, broker, nil
+	case "redis":
+		redisClient := newRedisClient(redisConfig)
+		_, err := events.RelayFromRedis(redisClient, broker)
+		if err != nil {
+			redisClient.Close()
+			return nil, nil, err
+		}
+		return events.NewRedisPublisher(redisClient), broker, nil
+	default:
+		return nil, nil, errors.New("Unknown events backend " + eventsConfig.Backend)
+	}
+}
 
+func InitRejections(rejectionConfig *RejectionConfig, redisConfig *RedisConfig) (rejections.Store, error) {
+	switch rejectionConfig.Backend {
+	case "memory":
+		return rejections.NewMemoryStore(rejectionConfig.TTL, rejectionConfig.Capacity), nil
+	case "redis":
+		return rejections.NewRedisStore(newRedisClient(redisConfig), rejectionConfig.TTL), nil
+	default:
+		return nil, errors.New("Unknown rejections backend " + rejectionConfig.Backend)
+	}
+}
+
+// InitOperatorAuth returns nil if operator routes are open, which is allowed in dev mode only
+func InitOperatorAuth(authConfig *AuthConfig) (router.Middleware, error) {
+	authorizers := []router.Authorizer{}
+	keys := []string{}
+	for _, key := range authConfig.APIKeys {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) != 0 {
+		authorizers = append(authorizers, router.APIKeys(keys))
+	}
+	if authConfig.HMACSecret != "" {
+		authorizers = append(authorizers, router.HMAC([]byte(authConfig.HMACSecret)))
+	}
+	if len(authorizers) == 0 {
+		if authConfig.DevMode {
+			fmt.Println("Operator routes are open in dev mode")
+			return nil, nil
+		}
+		return nil, errors.New("Operator routes need OPERATOR_API_KEYS or OPERATOR_HMAC_SECRET unless DEV_MODE is on")
+	}
+	return router.Auth(router.AnyOf(authorizers...)), nil
+}
+
+// InitBlockSigner returns the signer of assembled blocks
+func InitBlockSigner(signatureConfig *SignatureConfig, devMode bool) (signer.Signer, error) {
+	return initSigner(signatureConfig, signatureConfig.BlockSigningKey, signatureConfig.BlockKeystore, signatureConfig.BlockSignerURL, devMode)
+}
+
+// InitFundingTXSigner returns the signer of funding transactions for deposits
+func InitFundingTXSigner(signatureConfig *SignatureConfig, devMode bool) (signer.Signer, error) {
+	return initSigner(signatureConfig, signatureConfig.FundingTXSigningKey, signatureConfig.FundingTXKeystore, signatureConfig.FundingTXSignerURL, devMode)
+}
+
+func initSigner(signatureConfig *SignatureConfig, key string, keystorePath string, url string, devMode bool) (signer.Signer, error) {
+	var s signer.Signer
+	switch signatureConfig.Signer {
+	case "key":
+		keySigner, err := signer.NewKeySigner(common.FromHex(key))
+		if err != nil {
+			return nil, err
+		}
+		s = keySigner
+	case "keystore":
+		keySigner, err := signer.NewKeystoreSigner(keystorePath, signatureConfig.KeystorePassword)
+		if err != nil {
+			return nil, err
+		}
+		s = keySigner
+	case "remote":
+		remoteSigner, err := signer.NewRemoteSigner(url, signatureConfig.SignerTimeout)
+		if err != nil {
+			return nil, err
+		}
+		s = remoteSigner
+	default:
+		return nil, errors.New("Unknown signer " + signatureConfig.Signer)
+	}
+	err := signer.CheckNotDefault(s, devMode)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("Signing with " + s.Address().Hex())
+	return s, nil
+}
+
+// InitValidation returns nil if request bodies should not be checked
+func InitValidation(httpConfig *HTTPConfig) (router.Middleware, error) {
+	if httpConfig.OpenAPISpec == "" {
+		return nil, nil
+	}
+	spec, err := openapi.Load(httpConfig.OpenAPISpec)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.Validate(spec), nil
+}
+
+// InitTrustedProxies returns nil if no proxy is trusted
+func InitTrustedProxies(httpConfig *HTTPConfig) (router.Middleware, error) {
+	if len(httpConfig.TrustedProxies) == 0 {
+		return nil, nil
+	}
+	proxies, err := router.NewTrustedProxies(httpConfig.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	return router.RealIP(proxies), nil
+}
+
+// InitLastBlockHash records BLOCK_PARENT_HASH if the last written block is not archived
+func InitLastBlockHash(blockArchiveConfig *BlockArchiveConfig, db storage.Database) error {
+	if blockArchiveConfig.ParentHash == "" {
+		return nil
+	}
+	return foundationdb.RecordLastBlockHash(db, common.FromHex(blockArchiveConfig.ParentHash))
+}
+
+func InitSequencer(sequencerConfig *SequencerConfig, redisConfig *RedisConfig, db storage.Database) (sequencer.Sequencer, error) {
+	if sequencerConfig.MaxBlockTransactions < 0 || sequencerConfig.MaxBlockBytes < 0 {
+		return nil, errors.New("Block limits should not be negative")
+	}
+	limits := sequencer.BlockLimits{MaxTransactions: uint32(sequencerConfig.MaxBlockTransactions),
+		MaxBytes: uint64(sequencerConfig.MaxBlockBytes)}
+	switch sequencerConfig.Backend {
+	case "redis":
+		redisClient := newRedisClient(redisConfig)
+		seq, err := sequencer.NewRedisSequencer(redisClient, limits)
+		if err != nil {
+			redisClient.Close()
+			return nil, err
+		}
+		return seq, nil
+	case "local":
+		return sequencer.NewLocalSequencer(db, limits)
+	case "versionstamp":
+		return sequencer.NewVersionstampSequencer(db, limits)
+	default:
+		return nil, errors.New("Unknown sequencer " + sequencerConfig.Backend)
+	}
+}
+
+func InitStorage(storageConfig *StorageConfig, databaseConfig *FDBConfig) (storage.Database, error) {
+	switch storageConfig.Backend {
+	case "foundationdb":
+		return InitDB(databaseConfig)
+	case "embedded":
+		if storageConfig.EmbeddedPath == "" {
+			return nil, errors.New("Empty path for embedded database")
+		}
+		return boltstorage.Open(storageConfig.EmbeddedPath)
+	default:
+		return nil, errors.New("Unknown storage backend " + storageConfig.Backend)
+	}
 }
 
-func InitDB(config *FDBConfig) (*fdb.Database, error) {
+func InitDB(config *FDBConfig) (storage.Database, error) {
 	err := fdb.StartNetwork()
 	if err != nil {
 		return nil, err
 	}
 	if config.FdbRewriteClusterFile == false {
 		db := fdb.MustOpenDefault()
-		return &db, nil
+		return fdbstorage.NewDatabase(db), nil
 	}
 	if config.FdbClusterFilePath == "" {
 		return nil, errors.New("Empty content for cluster file rewriting")
@@ -105,5 +497,5 @@ func InitDB(config *FDBConfig) (*fdb.Database, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &db, nil
+	return fdbstorage.NewDatabase(db), nil
 }
@@ -0,0 +1,118 @@
+package events
+
+import (
+	"errors"
+	"sync"
+)
+
+// SubscriptionBufferSize is the number of events a subscriber may lag behind before
+// its subscription is closed
+const SubscriptionBufferSize = 256
+
+// MaxTopicsPerSubscription limits the number of addresses one client can watch
+const MaxTopicsPerSubscription = 100
+
+var (
+	// ErrTooManyTopics is returned when a subscription watches too many topics
+	ErrTooManyTopics = errors.New("Too many topics")
+	// ErrSubscriptionClosed is returned when watching a topic on a closed subscription
+	ErrSubscriptionClosed = errors.New("Subscription is closed")
+)
+
+// Broker delivers events to subscriptions within one process
+type Broker struct {
+	lock   sync.RWMutex
+	topics map[string]map[*Subscription]struct{}
+}
+
+// Subscription receives events of the topics it watches. Events is closed when the
+// subscription is closed, also when it lags behind by more than SubscriptionBufferSize events
+type Subscription struct {
+	Events <-chan *Event
+	events chan *Event
+	broker *Broker
+	topics map[string]struct{}
+	closed bool
+}
+
+func NewBroker() *Broker {
+	broker := &Broker{topics: make(map[string]map[*Subscription]struct{})}
+	return broker
+}
+
+func (b *Broker) Subscribe() *Subscription {
+	events := make(chan *Event, SubscriptionBufferSize)
+	subscription := &Subscription{Events: events, events: events, broker: b, topics: make(map[string]struct{})}
+	return subscription
+}
+
+// Publish never blocks, slow subscriptions are closed instead
+func (b *Broker) Publish(event *Event) {
+	slow := []*Subscription{}
+	b.lock.RLock()
+	for subscription := range b.topics[event.Topic] {
+		select {
+		case subscription.events <- event:
+		default:
+			slow = append(slow, subscription)
+		}
+	}
+	b.lock.RUnlock()
+	for _, subscription := range slow {
+		subscription.Close()
+	}
+}
+
+func (s *Subscription) Watch(topic string) error {
+	b := s.broker
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if s.closed {
+		return ErrSubscriptionClosed
+	}
+	if _, ok := s.topics[topic]; ok {
+		return nil
+	}
+	if len(s.topics) >= MaxTopicsPerSubscription {
+		return ErrTooManyTopics
+	}
+	s.topics[topic] = struct{}{}
+	subscriptions, ok := b.topics[topic]
+	if !ok {
+		subscriptions = make(map[*Subscription]struct{})
+		b.topics[topic] = subscriptions
+	}
+	subscriptions[s] = struct{}{}
+	return nil
+}
+
+func (s *Subscription) Unwatch(topic string) {
+	b := s.broker
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	s.unwatch(topic)
+}
+
+func (s *Subscription) Close() {
+	b := s.broker
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if s.closed {
+		return
+	}
+	for topic := range s.topics {
+		s.unwatch(topic)
+	}
+	s.closed = true
+	close(s.events)
+}
+
+// unwatch expects the broker lock to be held
+func (s *Subscription) unwatch(topic string) {
+	delete(s.topics, topic)
+	subscriptions := s.broker.topics[topic]
+	delete(subscriptions, s)
+	if len(subscriptions) == 0 {
+		delete(s.broker.topics, topic)
+	}
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"testing"
+)
+
+func TestBrokerDeliversWatchedTopics(t *testing.T) {
+	broker := NewBroker()
+	subscription := broker.Subscribe()
+	err := subscription.Watch(BlocksTopic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broker.Publish(&Event{Topic: "0x01", Type: UTXOCreated})
+	broker.Publish(&Event{Topic: BlocksTopic, Type: BlockWritten, BlockNumber: 1})
+	event := <-subscription.Events
+	if event.Type != BlockWritten || event.BlockNumber != 1 {
+		t.Fatal("Only watched topics should be delivered")
+	}
+	subscription.Unwatch(BlocksTopic)
+	broker.Publish(&Event{Topic: BlocksTopic, Type: BlockWritten, BlockNumber: 2})
+	if len(subscription.Events) != 0 {
+		t.Fatal("Unwatched topic should not be delivered")
+	}
+	if len(broker.topics) != 0 {
+		t.Fatal("Topic without subscriptions should be removed")
+	}
+}
+
+func TestBrokerClosesSlowSubscriptions(t *testing.T) {
+	broker := NewBroker()
+	slow := broker.Subscribe()
+	slow.Watch(BlocksTopic)
+	for i := 0; i <= SubscriptionBufferSize; i++ {
+		broker.Publish(&Event{Topic: BlocksTopic, Type: BlockWritten, BlockNumber: uint32(i)})
+	}
+	received := 0
+	for range slow.Events {
+		received++
+	}
+	if received != SubscriptionBufferSize {
+		t.Fatal("Buffered events should be delivered before closing")
+	}
+	if slow.Watch(BlocksTopic) != ErrSubscriptionClosed {
+		t.Fatal("Closed subscription can not watch topics")
+	}
+	for i := 0; i < MaxTopicsPerSubscription; i++ {
+		broker.Subscribe().Watch(BlocksTopic)
+	}
+	subscription := broker.Subscribe()
+	for i := 0; i < MaxTopicsPerSubscription; i++ {
+		subscription.Watch(string(rune('a' + i)))
+	}
+	if subscription.Watch(BlocksTopic) != ErrTooManyTopics {
+		t.Fatal("Number of topics should be limited")
+	}
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"strings"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaCommons/transaction"
+)
+
+// Event types pushed to subscribers
+const (
+	// UTXOCreated is published when a written block creates an output
+	UTXOCreated = "utxo_created"
+	// UTXOSpent is published when an accepted transaction spends an output
+	UTXOSpent = "utxo_spent"
+	// UTXOExitStarted is published when an exit makes an output non-spendable
+	UTXOExitStarted = "utxo_exit_started"
+	// BlockWritten is published when a block is written
+	BlockWritten = "block_written"
+)
+
+// BlocksTopic carries BlockWritten events, every other event goes to the topic of its address
+const BlocksTopic = "blocks"
+
+type Event struct {
+	Topic                string `json:"topic"`
+	Type                 string `json:"type"`
+	BlockNumber          uint32 `json:"blockNumber"`
+	TransactionNumber    uint32 `json:"transactionNumber,omitempty"`
+	OutputNumber         uint8  `json:"outputNumber,omitempty"`
+	NumberOfTransactions uint32 `json:"numberOfTransactions,omitempty"`
+	Value                string `json:"value,omitempty"`
+	Hash                 string `json:"hash,omitempty"`
+}
+
+// Publisher delivers events to subscribers, it never blocks the caller for long and
+// drops events it can not deliver
+type Publisher interface {
+	Publish(event *Event)
+}
+
+type discard struct{}
+
+func (discard) Publish(event *Event) {}
+
+// Discard is a Publisher for binaries that run without events
+var Discard Publisher = discard{}
+
+// AddressTopic is the topic of events for outputs of an address
+func AddressTopic(address common.Address) string {
+	return strings.ToLower(address.Hex())
+}
+
+// NewUTXOEvent takes a full UTXO index, that is the owner followed by the output number and value
+func NewUTXOEvent(eventType string, index [transaction.UTXOIndexLength]byte, hash []byte) *Event {
+	details := transaction.ParseIndexIntoUTXOdetails(index)
+	event := &Event{Topic: AddressTopic(common.BytesToAddress(index[:transaction.AddressLength])),
+		Type:              eventType,
+		BlockNumber:       details.BlockNumber,
+		TransactionNumber: details.TransactionNumber,
+		OutputNumber:      details.OutputNumber,
+		Value:             details.Value}
+	if len(hash) != 0 {
+		event.Hash = common.ToHex(hash)
+	}
+	return event
+}
+
+func NewBlockWrittenEvent(blockNumber uint32, numberOfTransactions uint32, hash []byte) *Event {
+	return &Event{Topic: BlocksTopic,
+		Type:                 BlockWritten,
+		BlockNumber:          blockNumber,
+		NumberOfTransactions: numberOfTransactions,
+		Hash:                 common.ToHex(hash)}
+}
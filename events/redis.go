@@ -0,0 +1,70 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	redis "github.com/go-redis/redis"
+)
+
+// EventsChannel is the Redis channel that carries events between binaries
+const EventsChannel = "plasma:events"
+
+// publishQueueSize bounds events waiting for Redis, later ones are dropped
+const publishQueueSize = 10000
+
+// RedisPublisher sends events to binaries that serve subscriptions
+type RedisPublisher struct {
+	client *redis.Client
+	queue  chan []byte
+}
+
+func NewRedisPublisher(client *redis.Client) *RedisPublisher {
+	publisher := &RedisPublisher{client, make(chan []byte, publishQueueSize)}
+	go publisher.run()
+	return publisher
+}
+
+// Publish queues an event, so Redis latency never slows down the caller
+func (p *RedisPublisher) Publish(event *Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	select {
+	case p.queue <- data:
+	default:
+		fmt.Println("Event queue is full, dropping event")
+	}
+}
+
+func (p *RedisPublisher) run() {
+	for data := range p.queue {
+		err := p.client.Publish(EventsChannel, data).Err()
+		if err != nil {
+			fmt.Println("Failed to publish event: " + err.Error())
+		}
+	}
+}
+
+// RelayFromRedis publishes events of other binaries to a local broker until the
+// returned PubSub is closed
+func RelayFromRedis(client *redis.Client, broker *Broker) (*redis.PubSub, error) {
+	pubsub := client.Subscribe(EventsChannel)
+	_, err := pubsub.Receive()
+	if err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	go func() {
+		for message := range pubsub.Channel() {
+			var event Event
+			err := json.Unmarshal([]byte(message.Payload), &event)
+			if err != nil {
+				continue
+			}
+			broker.Publish(&event)
+		}
+	}()
+	return pubsub, nil
+}
@@ -4,189 +4,154 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/rlp"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
-	"github.com/go-redis/redis"
+	"github.com/matterinc/PlasmaBlockCreator/sequencer"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	"github.com/matterinc/PlasmaCommons/block"
 	commonConst "github.com/matterinc/PlasmaCommons/common"
 	transaction "github.com/matterinc/PlasmaCommons/transaction"
 )
 
+// blockRecordsPerPage keeps every read of a block well below the 5 seconds and the size
+// limits of a FoundationDB transaction
+const blockRecordsPerPage = 10000
+
+// recordsToMovePerTransaction keeps moving records out of a full block below the transaction size limit
+const recordsToMovePerTransaction = 1000
+
 type BlockAssembler struct {
-	db          *fdb.Database
-	redisClient *redis.Client
+	db        storage.Database
+	sequencer sequencer.Sequencer
 }
 
-func NewBlockAssembler(db *fdb.Database, redisClient *redis.Client) *BlockAssembler {
-	reader := &BlockAssembler{db: db, redisClient: redisClient}
+func NewBlockAssembler(db storage.Database, sequencer sequencer.Sequencer) *BlockAssembler {
+	reader := &BlockAssembler{db: db, sequencer: sequencer}
 	return reader
 }
 
-func (r *BlockAssembler) checkIfBlockIsEmpty(blockNumber uint32) (uint64, error) {
-	blockNumberBuffer := make([]byte, transaction.BlockNumberLength)
-	binary.BigEndian.PutUint32(blockNumberBuffer, blockNumber)
-
-	nextBlockNumberBuffer := make([]byte, transaction.BlockNumberLength)
-	binary.BigEndian.PutUint32(nextBlockNumberBuffer, blockNumber+1)
-
-	pr, err := fdb.PrefixRange([]byte{})
-	if err != nil {
-		return 0, err
-	}
-
-	txNumberPadding := make([]byte, transaction.TransactionNumberLength)
-
-	fullBeginingIndex := []byte{}
-	fullBeginingIndex = append(fullBeginingIndex, commonConst.TransactionIndexPrefix...)
-	fullBeginingIndex = append(fullBeginingIndex, blockNumberBuffer...)
-	fullBeginingIndex = append(fullBeginingIndex, txNumberPadding...)
-
-	fullEndingIndex := []byte{}
-	fullEndingIndex = append(fullEndingIndex, commonConst.TransactionIndexPrefix...)
-	fullEndingIndex = append(fullEndingIndex, nextBlockNumberBuffer...)
-	fullEndingIndex = append(fullEndingIndex, txNumberPadding...)
-
-	pr.Begin = fdb.Key(fullBeginingIndex)
-	pr.End = fdb.Key(fullEndingIndex)
-
-	options := fdb.RangeOptions{}
-	options.Mode = fdb.StreamingMode(fdb.StreamingModeSmall)
-
+// checkIfBlockIsEmpty reads a single record of the block
+func (r *BlockAssembler) checkIfBlockIsEmpty(blockNumber uint32) (bool, error) {
 	start := time.Now()
-
-	ret, err := r.db.ReadTransact(func(tr fdb.ReadTransaction) (interface{}, error) {
-		values, err := tr.GetRange(pr, options).GetSliceWithError()
-		if err != nil {
-			return nil, err
-		}
-		return values, nil
-	})
+	found, err := CountPendingTransactions(r.db, blockNumber, 1)
 	if err != nil {
-		return 0, err
-	}
-	if ret == nil {
-		return 0, errors.New("Block is empty")
+		return false, err
 	}
-
 	elapsed := time.Since(start)
 	fmt.Println("Checking if block is empty taken " + fmt.Sprintf("%d", elapsed.Nanoseconds()/1000000) + " ms")
-
-	values := ret.([]fdb.KeyValue)
-	numValues := uint64(len(values))
-	if numValues == 0 {
-		return 0, errors.New("Block is empty")
-	}
-	return numValues, nil
+	return found == 0, nil
 }
 
-func (r *BlockAssembler) getRecordsForBlock(blockNumber uint32) ([]*transaction.SpendingRecord, error) {
-	blockNumberBuffer := make([]byte, transaction.BlockNumberLength)
-	binary.BigEndian.PutUint32(blockNumberBuffer, blockNumber)
-
-	nextBlockNumberBuffer := make([]byte, transaction.BlockNumberLength)
-	binary.BigEndian.PutUint32(nextBlockNumberBuffer, blockNumber+1)
+// blockTransactionsRange covers the spending records of a block, with counters or versionstamps
+func blockTransactionsRange(blockNumber uint32) storage.KeyRange {
+	begin := make([]byte, transaction.BlockNumberLength)
+	binary.BigEndian.PutUint32(begin, blockNumber)
+	end := make([]byte, transaction.BlockNumberLength)
+	binary.BigEndian.PutUint32(end, blockNumber+1)
+	return storage.KeyRange{Begin: append(append([]byte{}, commonConst.TransactionIndexPrefix...), begin...),
+		End: append(append([]byte{}, commonConst.TransactionIndexPrefix...), end...)}
+}
 
-	pr, err := fdb.PrefixRange([]byte{})
+// CountPendingTransactions counts spending records of a block that is not assembled yet,
+// counting stops at limit
+func CountPendingTransactions(db storage.Database, blockNumber uint32, limit int) (int, error) {
+	options := storage.RangeOptions{}
+	options.Limit = limit
+	options.Mode = storage.StreamingModeWantAll
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return tr.GetRange(blockTransactionsRange(blockNumber), options)
+	})
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	return len(ret.([]storage.KeyValue)), nil
+}
 
-	txNumberPadding := make([]byte, transaction.TransactionNumberLength)
-
-	fullBeginingIndex := []byte{}
-	fullBeginingIndex = append(fullBeginingIndex, commonConst.TransactionIndexPrefix...)
-	fullBeginingIndex = append(fullBeginingIndex, blockNumberBuffer...)
-	fullBeginingIndex = append(fullBeginingIndex, txNumberPadding...)
-
-	fullEndingIndex := []byte{}
-	fullEndingIndex = append(fullEndingIndex, commonConst.TransactionIndexPrefix...)
-	fullEndingIndex = append(fullEndingIndex, nextBlockNumberBuffer...)
-	fullEndingIndex = append(fullEndingIndex, txNumberPadding...)
-
-	pr.Begin = fdb.Key(fullBeginingIndex)
-	pr.End = fdb.Key(fullEndingIndex)
-
-	options := fdb.RangeOptions{}
-	options.Mode = fdb.StreamingMode(fdb.StreamingModeWantAll)
+func (r *BlockAssembler) getRecordsForBlock(blockNumber uint32) ([]*transaction.SpendingRecord, error) {
+	records, _, err := r.getRecordsAndKeysForBlock(blockNumber)
+	return records, err
+}
 
+// getRecordsAndKeysForBlock also returns the key of every record. Records are read in pages
+// of blockRecordsPerPage, every page in a read transaction of its own at the same version
+func (r *BlockAssembler) getRecordsAndKeysForBlock(blockNumber uint32) ([]*transaction.SpendingRecord, [][]byte, error) {
 	start := time.Now()
 
-	ret, err := r.db.ReadTransact(func(tr fdb.ReadTransaction) (interface{}, error) {
-		values, err := tr.GetRange(pr, options).GetSliceWithError()
-		if err != nil {
-			return nil, err
+	toReturn := []*transaction.SpendingRecord{}
+	keys := [][]byte{}
+	expectedKeyLength := len(commonConst.TransactionIndexPrefix) + transaction.BlockNumberLength + transaction.TransactionNumberLength
+	versionstampedKeyLength := len(commonConst.TransactionIndexPrefix) + transaction.BlockNumberLength + storage.VersionstampLength
+	err := storage.ReadPages(r.db, blockTransactionsRange(blockNumber), blockRecordsPerPage, func(page []storage.KeyValue) error {
+		for _, kv := range page {
+			key := kv.Key
+			value := kv.Value
+			if len(key) != expectedKeyLength && len(key) != versionstampedKeyLength {
+				continue
+			}
+			var newSpendingRecord transaction.SpendingRecord
+			err := rlp.DecodeBytes(value, &newSpendingRecord)
+			if err != nil {
+				return errors.New("Failed to deserialize spending record")
+			}
+			toReturn = append(toReturn, &newSpendingRecord)
+			keys = append(keys, key)
 		}
-		return values, nil
+		fmt.Println("Read " + strconv.Itoa(len(toReturn)) + " transactions for block " + strconv.Itoa(int(blockNumber)))
+		return nil
 	})
 	if err != nil {
-		return nil, err
-	}
-	if ret == nil {
-		return nil, errors.New("Could not write a transaction")
+		return nil, nil, err
 	}
 
 	elapsed := time.Since(start)
 	fmt.Println("Reading transactions for a new block taken " + fmt.Sprintf("%d", elapsed.Nanoseconds()/1000000) + " ms")
-
-	values := ret.([]fdb.KeyValue)
-	toReturn := []*transaction.SpendingRecord{}
-	expectedKeyLength := len(commonConst.TransactionIndexPrefix) + transaction.BlockNumberLength + transaction.TransactionNumberLength
-	for _, kv := range values {
-		key := kv.Key
-		value := kv.Value
-		if len(key) != expectedKeyLength {
-			continue
-		}
-		var newSpendingRecord transaction.SpendingRecord
-		err := rlp.DecodeBytes(value, &newSpendingRecord)
-		if err != nil {
-			return nil, errors.New("Failed to deserialize spending record")
-		}
-		toReturn = append(toReturn, &newSpendingRecord)
-	}
-	return toReturn, nil
+	return toReturn, keys, nil
 }
 
 func (r *BlockAssembler) AssembleBlock(newBlockNumber uint32, previousHash []byte, startNext bool) (*block.Block, error) {
-	newTXes, err := r.checkIfBlockIsEmpty(newBlockNumber)
+	empty, err := r.checkIfBlockIsEmpty(newBlockNumber)
+	if err != nil {
+		return nil, err
+	}
 	start := time.Now()
 	// if !startNext {
-	// 	if newTXes == 0 {
+	// 	if empty {
 	// 		return nil, nil
 	// 	}
 	// }
-	if newTXes == 0 {
+	if empty {
 		return nil, nil
 	}
-	newCounterToSet := (uint64(newBlockNumber+1) << (transaction.TransactionNumberLength * 8)) - 1
-	keys := make([]string, 1)
-	keys[0] = "ctr"
-	values := make([]string, 1)
-	values[0] = strconv.FormatUint(newCounterToSet, 10)
-	// fmt.Println("Setting new value to redis = " + values[0])
-	_, err = r.redisClient.EvalSha("8b071016ecfd75b7cce1c7d76591b4a4219b43cd", keys, values).Result()
+	err = r.sequencer.AdvanceToBlock(newBlockNumber)
 	if err != nil {
 		return nil, err
 	}
-	counterCheck, err := r.redisClient.Get(keys[0]).Uint64()
+	spendingRecords, keys, err := r.getRecordsAndKeysForBlock(newBlockNumber)
 	if err != nil {
 		return nil, err
-		// fmt.Println(err)
-		// os.Exit(1)
 	}
-	if counterCheck < newCounterToSet {
-		return nil, errors.New("New counter is less than expected")
-		// fmt.Println("Counter didn't increment")
-		// os.Exit(1)
-	}
-	spendingRecords, err := r.getRecordsForBlock(newBlockNumber)
+	fitting, err := recordsWithinLimits(r.sequencer.Limits(), spendingRecords)
 	if err != nil {
 		return nil, err
 	}
+	if fitting < len(spendingRecords) {
+		if !sequencer.AssignedOnCommit(r.sequencer) {
+			// counters were assigned under other limits, e.g. before the limits were lowered, and
+			// can not be renumbered into the next block where the sequencer may have given out the
+			// same counters. The block is assembled as it is, the new limits apply to later blocks
+			fmt.Println("Warning: block " + strconv.Itoa(int(newBlockNumber)) + " is over the size limit with " + strconv.Itoa(len(spendingRecords)) + " transactions, assembling it anyway")
+		} else {
+			err = r.moveRecordsToBlock(newBlockNumber+1, keys[fitting:], spendingRecords[fitting:])
+			if err != nil {
+				return nil, err
+			}
+			spendingRecords = spendingRecords[:fitting]
+		}
+	}
 	spendingTXes := []*transaction.SignedTransaction{}
 	// inputLookupHashmap := hashmap.New(uintptr(len(spendingRecords)))
 	for _, spendingRec := range spendingRecords {
@@ -210,3 +175,115 @@ func (r *BlockAssembler) AssembleBlock(newBlockNumber uint32, previousHash []byt
 
 	return newBlock, nil
 }
+
+// BlockPreview describes the block that AssembleBlock would produce at the moment
+type BlockPreview struct {
+	BlockNumber  uint32
+	Transactions int
+	// Deferred transactions would be moved to the next block by the size limits
+	Deferred   int
+	TotalValue *big.Int
+	MerkleRoot []byte
+	// FirstCounter and LastCounter are zero for versionstamped records
+	FirstCounter uint64
+	LastCounter  uint64
+}
+
+// PreviewBlock reads the pending transactions of a block and builds it without advancing
+// the sequencer, moving records or signing. Transactions can still join an open block
+// after the preview
+func (r *BlockAssembler) PreviewBlock(blockNumber uint32, previousHash []byte) (*BlockPreview, error) {
+	spendingRecords, keys, err := r.getRecordsAndKeysForBlock(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	preview := &BlockPreview{BlockNumber: blockNumber, TotalValue: big.NewInt(0)}
+	fitting := len(spendingRecords)
+	if sequencer.AssignedOnCommit(r.sequencer) {
+		fitting, err = recordsWithinLimits(r.sequencer.Limits(), spendingRecords)
+		if err != nil {
+			return nil, err
+		}
+	}
+	preview.Transactions = fitting
+	preview.Deferred = len(spendingRecords) - fitting
+	if fitting == 0 {
+		return preview, nil
+	}
+	counterKeyLength := len(commonConst.TransactionIndexPrefix) + transaction.BlockNumberLength + transaction.TransactionNumberLength
+	if len(keys[0]) == counterKeyLength && len(keys[fitting-1]) == counterKeyLength {
+		preview.FirstCounter = binary.BigEndian.Uint64(keys[0][len(commonConst.TransactionIndexPrefix):])
+		preview.LastCounter = binary.BigEndian.Uint64(keys[fitting-1][len(commonConst.TransactionIndexPrefix):])
+	}
+	spendingTXes := []*transaction.SignedTransaction{}
+	for _, spendingRec := range spendingRecords[:fitting] {
+		tx := spendingRec.SpendingTransaction
+		for _, output := range tx.UnsignedTransaction.Outputs {
+			preview.TotalValue.Add(preview.TotalValue, big.NewInt(0).SetBytes(output.Value[:]))
+		}
+		spendingTXes = append(spendingTXes, tx)
+	}
+	newBlock, err := block.NewBlock(blockNumber, spendingTXes, previousHash)
+	if err != nil {
+		return nil, err
+	}
+	preview.MerkleRoot = newBlock.BlockHeader.MerkleTreeRoot[:]
+	return preview, nil
+}
+
+// recordsWithinLimits returns how many records from the start of a block fit the limits
+func recordsWithinLimits(limits sequencer.BlockLimits, records []*transaction.SpendingRecord) (int, error) {
+	usedBytes := uint64(0)
+	for i, record := range records {
+		size := 0
+		if limits.MaxBytes != 0 {
+			_, raw, err := TransactionHash(record.SpendingTransaction)
+			if err != nil {
+				return 0, err
+			}
+			size = len(raw)
+		}
+		if !limits.Fits(uint32(i), usedBytes, size) {
+			return i, nil
+		}
+		usedBytes += uint64(size)
+	}
+	return len(records), nil
+}
+
+// moveRecordsToBlock rekeys versionstamped records into the next block. They keep their
+// versionstamps, so they come before the records that were written into that block already
+func (r *BlockAssembler) moveRecordsToBlock(blockNumber uint32, keys [][]byte, records []*transaction.SpendingRecord) error {
+	fmt.Println("Moving " + strconv.Itoa(len(keys)) + " transactions to block " + strconv.Itoa(int(blockNumber)))
+	for start := 0; start < len(keys); start += recordsToMovePerTransaction {
+		end := start + recordsToMovePerTransaction
+		if end > len(keys) {
+			end = len(keys)
+		}
+		_, err := r.db.Transact(func(tr storage.Transaction) (interface{}, error) {
+			for i := start; i < end; i++ {
+				value, err := tr.Get(keys[i]).Get()
+				if err != nil {
+					return nil, err
+				}
+				hash, _, err := TransactionHash(records[i].SpendingTransaction)
+				if err != nil {
+					return nil, err
+				}
+				newKey, offset := CreateVersionstampedTransactionIndex(blockNumber)
+				if len(keys[i]) != len(newKey) {
+					return nil, errors.New("Only versionstamped records can be moved")
+				}
+				copy(newKey[offset:], keys[i][offset:])
+				tr.Clear(keys[i])
+				tr.Set(newKey, value)
+				tr.Set(CreateTransactionHashIndex(hash), createPendingTransactionRecord(newKey))
+			}
+			return nil, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package foundationdb
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/matterinc/PlasmaCommons/block"
+	commonConst "github.com/matterinc/PlasmaCommons/common"
+	"github.com/matterinc/PlasmaCommons/transaction"
+)
+
+// Keyspaces of the block archive. Serialized blocks are split into chunks, as a single
+// value is limited to 100KB in FoundationDB
+var (
+	BlockArchivePrefix       = []byte("blockArchive")
+	BlockHeaderArchivePrefix = []byte("headerArchive")
+	BlockHashIndexPrefix     = []byte("blockHash")
+	// LastBlockHashKey holds the number and the hash of the last written block if it was
+	// written before blocks were archived
+	LastBlockHashKey = []byte("lastBlockHash")
+)
+
+var ErrBlockNotFound = errors.New("Block not found")
+
+// ErrLastBlockNotArchived means the parent of the next block is unknown, the last written
+// block predates the archive and no hash was recorded for it
+var ErrLastBlockNotArchived = errors.New("Last written block is not archived, set BLOCK_PARENT_HASH to its hash")
+
+const BlockHeaderLength = 4 + 4 + 32 + 32 + 1 + 32 + 32
+
+const BlockHashLength = 32
+
+const blockChunkLength = 90000
+
+// keeps every archive transaction well below the 10MB limit
+const blockChunksPerTransaction = 50
+
+type ArchivedBlockHeader struct {
+	BlockNumber          uint32
+	NumberOfTransactions uint32
+	ParentHash           []byte
+	MerkleTreeRoot       []byte
+	Hash                 []byte
+	RawHeader            []byte
+}
+
+// SerializeBlockHeader packs header fields in the same order as they are signed
+func SerializeBlockHeader(header *block.BlockHeader) []byte {
+	raw := make([]byte, 0, BlockHeaderLength)
+	raw = append(raw, header.BlockNumber[:]...)
+	raw = append(raw, header.NumberOfTransactions[:]...)
+	raw = append(raw, header.ParentHash[:]...)
+	raw = append(raw, header.MerkleTreeRoot[:]...)
+	raw = append(raw, header.V[:]...)
+	raw = append(raw, header.R[:]...)
+	raw = append(raw, header.S[:]...)
+	return raw
+}
+
+func createBlockArchiveIndex(prefix []byte, blockNumber uint32) []byte {
+	blockNumberBuffer := make([]byte, transaction.BlockNumberLength)
+	binary.BigEndian.PutUint32(blockNumberBuffer, blockNumber)
+	index := []byte{}
+	index = append(index, prefix...)
+	index = append(index, blockNumberBuffer...)
+	return index
+}
+
+func createBlockChunkIndex(blockNumber uint32, chunk uint32) []byte {
+	chunkBuffer := make([]byte, 4)
+	binary.BigEndian.PutUint32(chunkBuffer, chunk)
+	index := createBlockArchiveIndex(BlockArchivePrefix, blockNumber)
+	index = append(index, chunkBuffer...)
+	return index
+}
+
+func createBlockHashIndex(hash []byte) []byte {
+	index := []byte{}
+	index = append(index, BlockHashIndexPrefix...)
+	index = append(index, hash...)
+	return index
+}
+
+// writeBlockChunks stores serialized block in as many transactions as needed. Chunks are
+// not reachable until the header and hash index are written, so partial writes are harmless
+func writeBlockChunks(db storage.Database, blockNumber uint32, rawBlock []byte) error {
+	numChunks := len(rawBlock) / blockChunkLength
+	if len(rawBlock)%blockChunkLength != 0 {
+		numChunks++
+	}
+	for from := 0; from < numChunks; from += blockChunksPerTransaction {
+		to := from + blockChunksPerTransaction
+		if to > numChunks {
+			to = numChunks
+		}
+		_, err := db.Transact(func(tr storage.Transaction) (interface{}, error) {
+			for i := from; i < to; i++ {
+				end := (i + 1) * blockChunkLength
+				if end > len(rawBlock) {
+					end = len(rawBlock)
+				}
+				tr.Set(createBlockChunkIndex(blockNumber, uint32(i)), rawBlock[i*blockChunkLength:end])
+			}
+			return nil, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveBlockHeader is called in the same transaction that marks the block as written
+func archiveBlockHeader(tr storage.Transaction, blockNumber uint32, rawHeader []byte, hash []byte) {
+	blockNumberBuffer := make([]byte, transaction.BlockNumberLength)
+	binary.BigEndian.PutUint32(blockNumberBuffer, blockNumber)
+	record := append(append([]byte{}, rawHeader...), hash...)
+	tr.Set(createBlockArchiveIndex(BlockHeaderArchivePrefix, blockNumber), record)
+	tr.Set(createBlockHashIndex(hash), blockNumberBuffer)
+}
+
+func LookupBlockNumberByHash(db storage.Database, hash []byte) (uint32, error) {
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return tr.Get(createBlockHashIndex(hash)).Get()
+	})
+	if err != nil {
+		return 0, err
+	}
+	value := ret.([]byte)
+	if len(value) != transaction.BlockNumberLength {
+		return 0, ErrBlockNotFound
+	}
+	return binary.BigEndian.Uint32(value), nil
+}
+
+func GetArchivedBlockHeader(db storage.Database, blockNumber uint32) (*ArchivedBlockHeader, error) {
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return tr.Get(createBlockArchiveIndex(BlockHeaderArchivePrefix, blockNumber)).Get()
+	})
+	if err != nil {
+		return nil, err
+	}
+	record := ret.([]byte)
+	if len(record) != BlockHeaderLength+BlockHashLength {
+		return nil, ErrBlockNotFound
+	}
+	return parseArchivedBlockHeader(record), nil
+}
+
+func GetArchivedBlock(db storage.Database, blockNumber uint32) ([]byte, error) {
+	chunksRange, err := storage.PrefixRange(createBlockArchiveIndex(BlockArchivePrefix, blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	options := storage.RangeOptions{}
+	options.Mode = storage.StreamingModeWantAll
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		record, err := tr.Get(createBlockArchiveIndex(BlockHeaderArchivePrefix, blockNumber)).Get()
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 0 {
+			return nil, ErrBlockNotFound
+		}
+		values, err := tr.GetRange(chunksRange, options)
+		if err != nil {
+			return nil, err
+		}
+		rawBlock := []byte{}
+		for _, kv := range values {
+			rawBlock = append(rawBlock, kv.Value...)
+		}
+		return rawBlock, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret.([]byte), nil
+}
+
+// NextBlockToAssemble returns the number of the block after the last written one and the hash
+// of the last written block as its parent, the first block has a zero parent hash. A last
+// block that is not archived needs a hash recorded with RecordLastBlockHash
+func NextBlockToAssemble(db storage.Database) (uint32, []byte, error) {
+	lastBlock, err := GetLastWrittenBlock(db)
+	if err != nil {
+		return 0, nil, err
+	}
+	if lastBlock == 0 {
+		return 1, make([]byte, BlockHashLength), nil
+	}
+	header, err := GetArchivedBlockHeader(db, lastBlock)
+	if err == nil {
+		return lastBlock + 1, header.Hash, nil
+	}
+	if err != ErrBlockNotFound {
+		return 0, nil, err
+	}
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return tr.Get(LastBlockHashKey).Get()
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	record := ret.([]byte)
+	if len(record) != transaction.BlockNumberLength+BlockHashLength || binary.BigEndian.Uint32(record) != lastBlock {
+		return 0, nil, ErrLastBlockNotArchived
+	}
+	return lastBlock + 1, record[transaction.BlockNumberLength:], nil
+}
+
+// RecordLastBlockHash remembers the hash of the last written block for the next block, for
+// deployments with blocks written before the archive. Nothing is recorded for an archived block
+func RecordLastBlockHash(db storage.Database, hash []byte) error {
+	if len(hash) != BlockHashLength {
+		return errors.New("Invalid block hash length")
+	}
+	_, err := db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		lastBlock, err := tr.Get(commonConst.BlockNumberKey).Get()
+		if err != nil {
+			return nil, err
+		}
+		if len(lastBlock) != transaction.BlockNumberLength {
+			return nil, errors.New("No block is written yet")
+		}
+		header, err := tr.Get(createBlockArchiveIndex(BlockHeaderArchivePrefix, binary.BigEndian.Uint32(lastBlock))).Get()
+		if err != nil {
+			return nil, err
+		}
+		if len(header) != 0 {
+			return nil, nil
+		}
+		tr.Set(LastBlockHashKey, append(append([]byte{}, lastBlock...), hash...))
+		return nil, nil
+	})
+	return err
+}
+
+// parseArchivedBlockHeader takes a header record, that is the serialized header followed by its hash
+func parseArchivedBlockHeader(record []byte) *ArchivedBlockHeader {
+	rawHeader := record[:BlockHeaderLength]
+	header := &ArchivedBlockHeader{}
+	header.BlockNumber = binary.BigEndian.Uint32(rawHeader[0:4])
+	header.NumberOfTransactions = binary.BigEndian.Uint32(rawHeader[4:8])
+	header.ParentHash = rawHeader[8:40]
+	header.MerkleTreeRoot = rawHeader[40:72]
+	header.Hash = record[BlockHeaderLength:]
+	header.RawHeader = rawHeader
+	return header
+}
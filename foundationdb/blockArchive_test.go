@@ -0,0 +1,129 @@
+package foundationdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	commonConst "github.com/matterinc/PlasmaCommons/common"
+)
+
+func TestBlockArchiveSplitsLargeBlocks(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	rawBlock := make([]byte, blockChunkLength*blockChunksPerTransaction+blockChunkLength/2)
+	for i := range rawBlock {
+		rawBlock[i] = byte(i)
+	}
+	err := writeBlockChunks(db, 7, rawBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = GetArchivedBlock(db, 7)
+	if err == nil {
+		t.Fatal("Block should not be visible before its header is written")
+	}
+	rawHeader := make([]byte, BlockHeaderLength)
+	rawHeader[3] = 7
+	hash := bytes.Repeat([]byte{0xab}, BlockHashLength)
+	_, err = db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		archiveBlockHeader(tr, 7, rawHeader, hash)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockNumber, err := LookupBlockNumberByHash(db, hash)
+	if err != nil || blockNumber != 7 {
+		t.Fatal("Block hash index points to a wrong block")
+	}
+	header, err := GetArchivedBlockHeader(db, blockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.BlockNumber != 7 || bytes.Compare(header.Hash, hash) != 0 {
+		t.Fatal("Archived header does not match")
+	}
+	archived, err := GetArchivedBlock(db, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Compare(archived, rawBlock) != 0 {
+		t.Fatal("Archived block does not match")
+	}
+}
+
+func TestNextBlockFollowsLastWrittenBlock(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	blockNumber, previousHash, err := NextBlockToAssemble(db)
+	if err != nil || blockNumber != 1 || bytes.Compare(previousHash, make([]byte, BlockHashLength)) != 0 {
+		t.Fatal("First block should have a zero parent hash")
+	}
+	hash := bytes.Repeat([]byte{0xcd}, BlockHashLength)
+	_, err = db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		archiveBlockHeader(tr, 1, make([]byte, BlockHeaderLength), hash)
+		tr.Set(commonConst.BlockNumberKey, []byte{0, 0, 0, 1})
+		for _, counter := range []uint64{1<<32 + 5, 2<<32 + 0, 2<<32 + 1, 2<<32 + 2, 3<<32 + 0} {
+			key := make([]byte, 8)
+			binary.BigEndian.PutUint64(key, counter)
+			tr.Set(append(append([]byte{}, commonConst.TransactionIndexPrefix...), key...), []byte{0x01})
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockNumber, previousHash, err = NextBlockToAssemble(db)
+	if err != nil || blockNumber != 2 || bytes.Compare(previousHash, hash) != 0 {
+		t.Fatal("Next block should follow the archived one")
+	}
+	pending, err := CountPendingTransactions(db, 2, 10)
+	if err != nil || pending != 3 {
+		t.Fatal("Only transactions of the block should be counted")
+	}
+	pending, err = CountPendingTransactions(db, 2, 2)
+	if err != nil || pending != 2 {
+		t.Fatal("Counting should stop at the limit")
+	}
+}
+
+func TestNextBlockFollowsBlockWrittenBeforeArchive(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	_, err := db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		tr.Set(commonConst.BlockNumberKey, []byte{0, 0, 0, 7})
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = NextBlockToAssemble(db)
+	if err != ErrLastBlockNotArchived {
+		t.Fatal("Parent of a block that is not archived should be unknown")
+	}
+	hash := bytes.Repeat([]byte{0xab}, BlockHashLength)
+	err = RecordLastBlockHash(db, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockNumber, previousHash, err := NextBlockToAssemble(db)
+	if err != nil || blockNumber != 8 || bytes.Compare(previousHash, hash) != 0 {
+		t.Fatal("Next block should follow the recorded hash")
+	}
+	archivedHash := bytes.Repeat([]byte{0xcd}, BlockHashLength)
+	_, err = db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		archiveBlockHeader(tr, 8, make([]byte, BlockHeaderLength), archivedHash)
+		tr.Set(commonConst.BlockNumberKey, []byte{0, 0, 0, 8})
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = RecordLastBlockHash(db, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockNumber, previousHash, err = NextBlockToAssemble(db)
+	if err != nil || blockNumber != 9 || bytes.Compare(previousHash, archivedHash) != 0 {
+		t.Fatal("Archived blocks should not take the recorded hash")
+	}
+}
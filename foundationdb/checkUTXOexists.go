@@ -3,23 +3,30 @@ package foundationdb
 import (
 	"errors"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	commonConst "github.com/matterinc/PlasmaCommons/common"
 	transaction "github.com/matterinc/PlasmaCommons/transaction"
 )
 
+var (
+	// ErrFundingTransaction is returned when a funding transaction is submitted for spending
+	ErrFundingTransaction = errors.New("Funding TXes are not valid as spending TXes")
+	// ErrUTXONotFound is returned when an input refers to an output that can not be spent
+	ErrUTXONotFound = errors.New("UTXO doesn't exist or invalid")
+)
+
 type UTXOReader struct {
-	db *fdb.Database
+	db storage.Database
 }
 
-func NewUTXOReader(db *fdb.Database) *UTXOReader {
+func NewUTXOReader(db storage.Database) *UTXOReader {
 	reader := &UTXOReader{db: db}
 	return reader
 }
 
 func (r *UTXOReader) CheckIfUTXOsExist(tx *transaction.SignedTransaction) error {
 	if tx.UnsignedTransaction.TransactionType[0] == transaction.TransactionTypeFund {
-		return errors.New("Funding TXes are not valid as spending TXes")
+		return ErrFundingTransaction
 	}
 	numInputs := len(tx.UnsignedTransaction.Inputs)
 	utxosToCheck := make([][]byte, numInputs)
@@ -33,14 +40,14 @@ func (r *UTXOReader) CheckIfUTXOsExist(tx *transaction.SignedTransaction) error
 		idx = append(idx, index[:]...)
 		utxosToCheck[i] = idx
 	}
-	_, err := r.db.ReadTransact(func(tr fdb.ReadTransaction) (interface{}, error) {
+	_, err := r.db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
 		for _, index := range utxosToCheck {
-			status, err := tr.Snapshot().Get(fdb.Key(index)).Get()
+			status, err := tr.Snapshot().Get(index).Get()
 			if err != nil {
 				return nil, err
 			}
 			if len(status) != 1 || status[0] != commonConst.UTXOisReadyForSpending {
-				return nil, errors.New("UTXO doesn't exist or invalid")
+				return nil, ErrUTXONotFound
 			}
 		}
 		return nil, nil
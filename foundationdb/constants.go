@@ -3,8 +3,9 @@ package foundationdb
 import (
 	"encoding/binary"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	commonConst "github.com/matterinc/PlasmaCommons/common"
+	"github.com/matterinc/PlasmaCommons/transaction"
 )
 
 func CreateTransactionIndex(counter uint64) []byte {
@@ -15,9 +16,21 @@ func CreateTransactionIndex(counter uint64) []byte {
 	return transactionIndex
 }
 
-func GetLastWrittenBlock(db *fdb.Database) (uint32, error) {
-	ret, err := db.ReadTransact(func(tr fdb.ReadTransaction) (interface{}, error) {
-		return tr.Get(fdb.Key(commonConst.BlockNumberKey)).Get()
+// CreateVersionstampedTransactionIndex returns a spending record key inside a block with a
+// placeholder for a commit versionstamp and the offset of the placeholder
+func CreateVersionstampedTransactionIndex(blockNumber uint32) ([]byte, int) {
+	blockNumberBuffer := make([]byte, transaction.BlockNumberLength)
+	binary.BigEndian.PutUint32(blockNumberBuffer, blockNumber)
+	transactionIndex := []byte(commonConst.TransactionIndexPrefix)
+	transactionIndex = append(transactionIndex, blockNumberBuffer...)
+	offset := len(transactionIndex)
+	transactionIndex = append(transactionIndex, make([]byte, storage.VersionstampLength)...)
+	return transactionIndex, offset
+}
+
+func GetLastWrittenBlock(db storage.Database) (uint32, error) {
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return tr.Get(commonConst.BlockNumberKey).Get()
 	})
 	if err != nil {
 		return 0, err
@@ -33,9 +46,9 @@ func GetLastWrittenBlock(db *fdb.Database) (uint32, error) {
 	return lastBlock, nil
 }
 
-func GetLastWrittenTransactionAndBlock(db *fdb.Database) (uint32, uint32, error) {
-	ret, err := db.ReadTransact(func(tr fdb.ReadTransaction) (interface{}, error) {
-		return tr.Get(fdb.Key(commonConst.TransactionNumberKey)).Get()
+func GetLastWrittenTransactionAndBlock(db storage.Database) (uint32, uint32, error) {
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return tr.Get(commonConst.TransactionNumberKey).Get()
 	})
 	if err != nil {
 		return 0, 0, err
@@ -6,20 +6,22 @@ import (
 	"errors"
 	"io"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
 	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/sequencer"
+	"github.com/matterinc/PlasmaBlockCreator/signer"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	commonConst "github.com/matterinc/PlasmaCommons/common"
 	"github.com/matterinc/PlasmaCommons/transaction"
 	types "github.com/matterinc/PlasmaCommons/types"
 )
 
 type FundingTXcreator struct {
-	db         *fdb.Database
-	signingKey []byte
+	db     storage.Database
+	signer signer.Signer
 }
 
-func NewFundingTXcreator(db *fdb.Database, signingKey []byte) *FundingTXcreator {
-	reader := &FundingTXcreator{db: db, signingKey: signingKey}
+func NewFundingTXcreator(db storage.Database, signer signer.Signer) *FundingTXcreator {
+	reader := &FundingTXcreator{db: db, signer: signer}
 	return reader
 }
 
@@ -34,36 +36,15 @@ func (r *FundingTXcreator) CreateFundingTX(to common.Address,
 	counterBuffer := make([]byte, 8)
 	binary.BigEndian.PutUint64(counterBuffer, counter)
 
-	depositIndexKey := []byte{}
-	depositIndexKey = append(depositIndexKey, commonConst.DepositIndexPrefix...)
-	depositIndexBytes, err := depositIndex.GetLeftPaddedBytes(32)
-	if err != nil {
-		return err
-	}
-	depositIndexKey = append(depositIndexKey, depositIndexBytes...)
-
-	fundingTX, err := transaction.CreateRawFundingTX(to, value, depositIndex, r.signingKey)
-	if err != nil {
-		return err
-	}
-	err = fundingTX.Validate()
-	if err != nil {
-		return err
-	}
-	spendingRecord := transaction.NewSpendingRecord(fundingTX, [][transaction.UTXOIndexLength]byte{})
-
-	var b bytes.Buffer
-	i := io.Writer(&b)
-	err = spendingRecord.EncodeRLP(i)
+	depositIndexKey, spendingRecordRaw, hash, err := r.prepareFundingTX(to, value, depositIndex)
 	if err != nil {
 		return err
 	}
-	spendingRecordRaw := b.Bytes() // SpendingRecord.RLPencode()
 
 	transactionIndex := CreateTransactionIndex(counter)
 
-	_, err = r.db.Transact(func(tr fdb.Transaction) (interface{}, error) {
-		existing, err := tr.Get(fdb.Key(depositIndexKey)).Get() // check for existing deposit
+	_, err = r.db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		existing, err := tr.Get(depositIndexKey).Get() // check for existing deposit
 		if err != nil {
 			return nil, err
 		}
@@ -71,7 +52,7 @@ func (r *FundingTXcreator) CreateFundingTX(to common.Address,
 			tr.Reset()
 			return nil, errors.New("Duplicate funding transaction")
 		}
-		existing, err = tr.Get(fdb.Key(transactionIndex)).Get()
+		existing, err = tr.Get(transactionIndex).Get()
 		if err != nil {
 			tr.Reset()
 			return nil, err
@@ -80,9 +61,10 @@ func (r *FundingTXcreator) CreateFundingTX(to common.Address,
 			tr.Reset()
 			return nil, errors.New("Counter is reused")
 		}
-		tr.Set(fdb.Key(depositIndexKey), counterBuffer)
-		tr.Set(fdb.Key(transactionIndex), spendingRecordRaw)
-		existing, err = tr.Get(fdb.Key(transactionIndex)).Get()
+		tr.Set(depositIndexKey, counterBuffer)
+		tr.Set(transactionIndex, spendingRecordRaw)
+		tr.Set(CreateTransactionHashIndex(hash), createPendingTransactionRecord(transactionIndex))
+		existing, err = tr.Get(transactionIndex).Get()
 		if err != nil {
 			tr.Reset()
 			return nil, err
@@ -98,3 +80,77 @@ func (r *FundingTXcreator) CreateFundingTX(to common.Address,
 	}
 	return nil
 }
+
+// CreateFundingTXInOpenBlock puts a funding transaction into the block that is currently
+// open for new transactions, ordered by the commit versionstamp instead of a counter
+func (r *FundingTXcreator) CreateFundingTXInOpenBlock(to common.Address,
+	value *types.BigInt,
+	depositIndex *types.BigInt) error {
+	depositIndexKey, spendingRecordRaw, hash, err := r.prepareFundingTX(to, value, depositIndex)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		existing, err := tr.Get(depositIndexKey).Get() // check for existing deposit
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) != 0 {
+			tr.Reset()
+			return nil, errors.New("Duplicate funding transaction")
+		}
+		blockNumber, err := sequencer.ReadBlockEpoch(tr)
+		if err != nil {
+			tr.Reset()
+			return nil, err
+		}
+		// the exact position is not known before commit, the first counter of the block is kept instead
+		counterBuffer := make([]byte, 8)
+		binary.BigEndian.PutUint64(counterBuffer, sequencer.LastCounterOfBlock(blockNumber-1)+1)
+		tr.Set(depositIndexKey, counterBuffer)
+		transactionIndex, offset := CreateVersionstampedTransactionIndex(blockNumber)
+		tr.SetVersionstampedKey(transactionIndex, offset, spendingRecordRaw)
+		tr.SetVersionstampedValue(CreateTransactionHashIndex(hash), createPendingTransactionRecord(transactionIndex), 1+offset)
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *FundingTXcreator) prepareFundingTX(to common.Address,
+	value *types.BigInt,
+	depositIndex *types.BigInt) ([]byte, []byte, []byte, error) {
+	depositIndexKey := []byte{}
+	depositIndexKey = append(depositIndexKey, commonConst.DepositIndexPrefix...)
+	depositIndexBytes, err := depositIndex.GetLeftPaddedBytes(32)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	depositIndexKey = append(depositIndexKey, depositIndexBytes...)
+
+	fundingTX, err := signer.CreateFundingTX(r.signer, to, value, depositIndex)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = fundingTX.Validate()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	spendingRecord := transaction.NewSpendingRecord(fundingTX, [][transaction.UTXOIndexLength]byte{})
+
+	var b bytes.Buffer
+	i := io.Writer(&b)
+	err = spendingRecord.EncodeRLP(i)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	spendingRecordRaw := b.Bytes() // SpendingRecord.RLPencode()
+	hash, _, err := TransactionHash(fundingTX)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return depositIndexKey, spendingRecordRaw, hash, nil
+}
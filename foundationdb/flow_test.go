@@ -0,0 +1,288 @@
+package foundationdb
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/sequencer"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/matterinc/PlasmaCommons/block"
+	"github.com/matterinc/PlasmaCommons/transaction"
+	"github.com/matterinc/PlasmaCommons/types"
+)
+
+var testOwner = common.HexToAddress("0x627306090abab3a6e1400e9345bc60c78a8bef57")
+var testOwnerKey = common.FromHex("0xc87509a1c067bbde78beb793e6fa76530b6382a4c0241e5e4a9ec0a0f44dc0d3")
+var testRecipient = common.HexToAddress("0xf17f52151ebef6c7334fad080c5704d77216b732")
+var testAmount = "1000000000000000000"
+
+func createTestTransfer(blockNumber int, txNumberInBlock int, outputNumberInTransaction int, value string, to common.Address, privateKey []byte) ([]byte, error) {
+	bn := types.NewBigInt(int64(blockNumber))
+	tn := types.NewBigInt(int64(txNumberInBlock))
+	in := types.NewBigInt(int64(outputNumberInTransaction))
+	v := types.NewBigInt(0)
+	v.SetString(value, 10)
+	input := &transaction.TransactionInput{}
+	err := input.SetFields(bn, tn, in, v)
+	if err != nil {
+		return nil, err
+	}
+	output := &transaction.TransactionOutput{}
+	err = output.SetFields(types.NewBigInt(0), to, v)
+	if err != nil {
+		return nil, err
+	}
+	inputs := []*transaction.TransactionInput{input}
+	outputs := []*transaction.TransactionOutput{output}
+	tx, err := transaction.NewUnsignedTransaction(transaction.TransactionTypeSplit, inputs, outputs)
+	if err != nil {
+		return nil, err
+	}
+	emptyBytes := [32]byte{}
+	signed, err := transaction.NewSignedTransaction(tx, []byte{0x00}, emptyBytes[:], emptyBytes[:])
+	if err != nil {
+		return nil, err
+	}
+	err = signed.Sign(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	err = signed.EncodeRLP(io.Writer(&b))
+	if err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func TestSpendWriteAndExitFlow(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	seq, err := sequencer.NewLocalSequencer(db, sequencer.BlockLimits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := types.NewBigInt(0)
+	value.SetString(testAmount, 10)
+	err = NewTestUTXOcreator(db).InsertUTXO(testOwner, 1, 0, 0, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := createTestTransfer(1, 0, 0, testAmount, testRecipient, testOwnerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := transaction.NewTransactionParser(1).Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = NewUTXOReader(db).CheckIfUTXOsExist(&parsed.TX)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := NewUTXOWriter(db, 1)
+	counter, err := seq.Next(len(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = writer.WriteSpending(parsed, counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spentCounter := counter
+	counter, err = seq.Next(len(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = writer.WriteSpending(parsed, counter)
+	if err == nil {
+		t.Fatal("Double spend was accepted")
+	}
+	hash, _, err := TransactionHash(&parsed.TX)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := LookupTransaction(db, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found.Pending || found.BlockNumber != 1 {
+		t.Fatal("Accepted transaction should be pending for block 1")
+	}
+
+	current, err := seq.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	preview, err := NewBlockAssembler(db, seq).PreviewBlock(1, make([]byte, block.PreviousBlockHashLength))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Transactions != 1 || preview.TotalValue.String() != testAmount {
+		t.Fatal("Preview should show a single transaction moving the test amount")
+	}
+	if preview.FirstCounter != spentCounter || preview.LastCounter != spentCounter {
+		t.Fatal("Preview shows a wrong counter range")
+	}
+	afterPreview, err := seq.Current()
+	if err != nil || afterPreview != current {
+		t.Fatal("Preview should not advance the sequencer")
+	}
+
+	newBlock, err := NewBlockAssembler(db, seq).AssembleBlock(1, make([]byte, block.PreviousBlockHashLength), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newBlock == nil || len(newBlock.Transactions) != 1 {
+		t.Fatal("Expected a single transaction in the block")
+	}
+	if !bytes.Equal(preview.MerkleRoot, newBlock.BlockHeader.MerkleTreeRoot[:]) {
+		t.Fatal("Preview and block Merkle roots differ")
+	}
+	counter, err = seq.Next(len(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counter <= sequencer.LastCounterOfBlock(1) {
+		t.Fatal("Sequencer was not advanced past the assembled block")
+	}
+	err = NewBlockWriter(db).WriteBlock(*newBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastBlock, err := GetLastWrittenBlock(db)
+	if err != nil || lastBlock != 1 {
+		t.Fatal("Last written block was not updated")
+	}
+	found, err = LookupTransaction(db, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Pending || found.BlockNumber != 1 || found.TransactionNumber != 0 || bytes.Compare(found.RawTransaction, raw) != 0 {
+		t.Fatal("Written transaction should be included at 1/0")
+	}
+
+	utxos, err := NewUTXOlister(db).GetUTXOsForAddress(testRecipient, 0, 0, 0, 10, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 {
+		t.Fatal("Recipient should own exactly one UTXO")
+	}
+
+	spentIndex := types.NewBigInt(0)
+	spentIndex.SetBytes(transaction.PackUTXOnumber(1, 0, 0))
+	marked, err := NewWithdrawTXMarker(db).MarkTX(testOwner, spentIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if marked {
+		t.Fatal("Exit of a spent UTXO should not be marked")
+	}
+	lookup, err := LookupSpendingIndex(db, spentIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lookup.BlockNumber != 1 || lookup.TransactionNumber != 0 || lookup.InputNumber != 0 {
+		t.Fatal("Spending index points to a wrong transaction")
+	}
+}
+
+func TestVersionstampOrderingClosesBlocks(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	seq, err := sequencer.NewVersionstampSequencer(db, sequencer.BlockLimits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := types.NewBigInt(0)
+	value.SetString(testAmount, 10)
+	err = NewTestUTXOcreator(db).InsertUTXO(testOwner, 1, 0, 0, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := createTestTransfer(1, 0, 0, testAmount, testRecipient, testOwnerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := transaction.NewTransactionParser(1).Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = NewUTXOWriter(db, 1).WriteSpendingInOpenBlock(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assembler := NewBlockAssembler(db, seq)
+	newBlock, err := assembler.AssembleBlock(1, make([]byte, block.PreviousBlockHashLength), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newBlock == nil || len(newBlock.Transactions) != 1 {
+		t.Fatal("Expected a single transaction in the block")
+	}
+	records, err := assembler.getRecordsForBlock(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Fatal("Next block should be empty")
+	}
+	current, err := seq.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxCounter, err := GetMaxTransactionCounter(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxCounter != sequencer.LastCounterOfBlock(1) || current < maxCounter {
+		t.Fatal("Versionstamped block should be treated as full")
+	}
+}
+
+func TestCounterBlockOverLimitsIsAssembled(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	seq, err := sequencer.NewLocalSequencer(db, sequencer.BlockLimits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := types.NewBigInt(0)
+	value.SetString(testAmount, 10)
+	writer := NewUTXOWriter(db, 1)
+	for i := 0; i < 2; i++ {
+		err = NewTestUTXOcreator(db).InsertUTXO(testOwner, 1, 0, uint8(i), value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := createTestTransfer(1, 0, i, testAmount, testRecipient, testOwnerKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := transaction.NewTransactionParser(1).Parse(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counter, err := seq.Next(len(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = writer.WriteSpending(parsed, counter)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the limit was lowered after both counters of block 1 were given out
+	lowered, err := sequencer.NewLocalSequencer(db, sequencer.BlockLimits{MaxTransactions: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newBlock, err := NewBlockAssembler(db, lowered).AssembleBlock(1, make([]byte, block.PreviousBlockHashLength), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newBlock == nil || len(newBlock.Transactions) != 2 {
+		t.Fatal("Block over the limits should be assembled with all of its transactions")
+	}
+}
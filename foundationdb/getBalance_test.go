@@ -0,0 +1,102 @@
+package foundationdb
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	commonConst "github.com/matterinc/PlasmaCommons/common"
+	"github.com/matterinc/PlasmaCommons/transaction"
+)
+
+func TestBalanceIsSummedOverManyPages(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	numUTXOs := balancePageSize*2 + 10
+	_, err := db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		for i := 0; i < numUTXOs; i++ {
+			key := []byte{}
+			key = append(key, commonConst.UtxoIndexPrefix...)
+			key = append(key, testOwner[:]...)
+			index := make([]byte, transaction.BlockNumberLength+transaction.TransactionNumberLength+transaction.OutputNumberLength+transaction.ValueLength)
+			binary.BigEndian.PutUint32(index, uint32(i+1))
+			index[len(index)-1] = 2
+			key = append(key, index...)
+			state := commonConst.UTXOisReadyForSpending
+			if i%10 == 0 {
+				state = commonConst.UTXOexistsButNotSpendable
+			}
+			tr.Set(key, []byte{state})
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance, err := NewUTXOlister(db).GetBalanceForAddress(testOwner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exiting := int64((numUTXOs + 9) / 10)
+	if balance.Total.Int64() != int64(numUTXOs*2) || balance.ExitPending.Int64() != exiting*2 ||
+		balance.Spendable.Int64() != int64(numUTXOs*2)-exiting*2 {
+		t.Fatal("Balance is not summed over all pages")
+	}
+	balance, err = NewUTXOlister(db).GetBalanceForAddress(testRecipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Total.Sign() != 0 {
+		t.Fatal("Other address should have no balance")
+	}
+}
+
+func TestListingSkipsFilteredOutputsAcrossPages(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	_, err := db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		for i := 0; i < 30; i++ {
+			key := []byte{}
+			key = append(key, commonConst.UtxoIndexPrefix...)
+			key = append(key, testOwner[:]...)
+			index := make([]byte, transaction.BlockNumberLength+transaction.TransactionNumberLength+transaction.OutputNumberLength+transaction.ValueLength)
+			binary.BigEndian.PutUint32(index, uint32(i+1))
+			state := commonConst.UTXOisReadyForSpending
+			if i%3 == 0 {
+				state = commonConst.UTXOexistsButNotSpendable
+			}
+			tr.Set(append(key, index...), []byte{state})
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lister := NewUTXOlister(db)
+	from := [transaction.UTXOIndexLength]byte{}
+	listed := []ListedUTXO{}
+	for pages := 0; ; pages++ {
+		page, hasMore, err := lister.ListUTXOsForAddress(testOwner, from, pages != 0, UTXOStateExitPending, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, utxo := range page {
+			if !utxo.ExitPending {
+				t.Fatal("Only outputs with a started exit should be listed")
+			}
+		}
+		listed = append(listed, page...)
+		if !hasMore {
+			break
+		}
+		if len(page) != 3 {
+			t.Fatal("Page with more outputs after it should be full")
+		}
+		from = page[len(page)-1].Index
+	}
+	if len(listed) != 10 {
+		t.Fatal("Every output with a started exit should be listed once")
+	}
+	all, hasMore, err := lister.ListUTXOsForAddress(testOwner, [transaction.UTXOIndexLength]byte{}, false, UTXOStateAny, 30)
+	if err != nil || len(all) != 30 || hasMore {
+		t.Fatal("All outputs should fit into one page")
+	}
+}
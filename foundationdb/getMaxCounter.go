@@ -3,28 +3,29 @@ package foundationdb
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"strconv"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
+	"github.com/matterinc/PlasmaBlockCreator/sequencer"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	commonConst "github.com/matterinc/PlasmaCommons/common"
+	"github.com/matterinc/PlasmaCommons/transaction"
 )
 
-func GetMaxTransactionCounter(db *fdb.Database) (uint64, error) {
+func GetMaxTransactionCounter(db storage.Database) (uint64, error) {
 	prefix := commonConst.TransactionIndexPrefix
 
-	maxNumberBuffer := make([]byte, 8)
-	binary.BigEndian.PutUint64(maxNumberBuffer, ^uint64(0))
-
-	fullPrefix := []byte{}
-	fullPrefix = append(fullPrefix, prefix...)
-	fullPrefix = append(fullPrefix, maxNumberBuffer...)
-
-	keySelector := fdb.LastLessOrEqual(fdb.Key(fullPrefix))
+	pr, err := storage.PrefixRange(prefix)
+	if err != nil {
+		return uint64(0), err
+	}
 
-	// options := fdb.RangeOptions{}
-	// options.Mode = fdb.StreamingMode(fdb.StreamingModeExact)
+	options := storage.RangeOptions{}
+	options.Limit = 1
+	options.Reverse = true
 
-	ret, err := db.Transact(func(tr fdb.Transaction) (interface{}, error) {
-		values, err := tr.GetKey(keySelector).Get()
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		values, err := tr.GetRange(pr, options)
 		if err != nil {
 			return nil, err
 		}
@@ -36,14 +37,42 @@ func GetMaxTransactionCounter(db *fdb.Database) (uint64, error) {
 	if ret == nil {
 		return uint64(0), nil
 	}
-	key := ret.(fdb.Key)
-	if len(key) == 0 {
+	values := ret.([]storage.KeyValue)
+	if len(values) == 0 {
 		return uint64(0), nil
 	}
+	key := values[0].Key
 	slice := key[len(prefix):]
+	if len(slice) == transaction.BlockNumberLength+storage.VersionstampLength {
+		// positions inside a block with versionstamped records are not known, treat it as full
+		return sequencer.LastCounterOfBlock(binary.BigEndian.Uint32(slice)), nil
+	}
 	if len(slice) != 8 {
 		return uint64(0), errors.New("Key length is invalid")
 	}
 	maxCounter := binary.BigEndian.Uint64(slice)
 	return maxCounter, nil
 }
+
+// CheckSequencer fails if the database already has a transaction with a counter
+// the sequencer has not issued yet, so new transactions could overwrite it
+func CheckSequencer(db storage.Database, seq sequencer.Sequencer) error {
+	fmt.Println("Testing for the sequencer counter")
+	sequencerCounter, err := seq.Current()
+	if err != nil {
+		return err
+	}
+	fmt.Println("Sequencer counter = ", strconv.FormatUint(sequencerCounter, 10))
+
+	fmt.Println("Testing for the database")
+	maxCounterInDatabase, err := GetMaxTransactionCounter(db)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Database counter = ", strconv.FormatUint(maxCounterInDatabase, 10))
+
+	if maxCounterInDatabase > sequencerCounter {
+		return errors.New("Counters mismatch")
+	}
+	return nil
+}
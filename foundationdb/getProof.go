@@ -0,0 +1,63 @@
+package foundationdb
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/matterinc/PlasmaBlockCreator/merkle"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/matterinc/PlasmaCommons/block"
+)
+
+// ErrMerkleRootMismatch means the rebuilt tree does not match the signed header
+var ErrMerkleRootMismatch = errors.New("Merkle root mismatch")
+
+type TransactionProof struct {
+	BlockNumber       uint32
+	TransactionNumber uint32
+	RawTransaction    []byte
+	Branch            [][]byte
+	Root              []byte
+}
+
+// GetTransactionProof rebuilds the Merkle tree of an archived block with PlasmaCommons and
+// returns a branch for one of its transactions. The tree is still checked against the root
+// in the signed header, so a corrupted archive never produces a proof
+func GetTransactionProof(db storage.Database, blockNumber uint32, transactionNumber uint32) (*TransactionProof, error) {
+	header, err := GetArchivedBlockHeader(db, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if transactionNumber >= header.NumberOfTransactions {
+		return nil, errors.New("Transaction number is out of range")
+	}
+	rawBlock, err := GetArchivedBlock(db, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	archivedBlock, err := block.NewBlockFromBytes(rawBlock)
+	if err != nil {
+		return nil, err
+	}
+	if int(transactionNumber) >= len(archivedBlock.Transactions) {
+		return nil, errors.New("Transaction number is out of range")
+	}
+	_, rawTransaction, err := TransactionHash(archivedBlock.Transactions[transactionNumber])
+	if err != nil {
+		return nil, err
+	}
+	tree, err := merkle.Tree(archivedBlock.Transactions)
+	if err != nil {
+		return nil, err
+	}
+	root := tree.MerkleRoot()
+	if bytes.Compare(root, header.MerkleTreeRoot) != 0 {
+		return nil, ErrMerkleRootMismatch
+	}
+	branch, err := merkle.Proof(tree, int(transactionNumber))
+	if err != nil {
+		return nil, err
+	}
+	proof := &TransactionProof{blockNumber, transactionNumber, rawTransaction, branch, root}
+	return proof, nil
+}
@@ -0,0 +1,81 @@
+package foundationdb
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/matterinc/PlasmaBlockCreator/merkle"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/matterinc/PlasmaCommons/block"
+	"github.com/matterinc/PlasmaCommons/transaction"
+)
+
+// archiveTestBlock stores a block the way BlockWriter does without writing its outputs
+func archiveTestBlock(db storage.Database, blockNumber uint32, newBlock *block.Block) error {
+	rawBlock, err := newBlock.Serialize()
+	if err != nil {
+		return err
+	}
+	blockHash, err := newBlock.BlockHeader.GetHash()
+	if err != nil {
+		return err
+	}
+	err = writeBlockChunks(db, blockNumber, rawBlock)
+	if err != nil {
+		return err
+	}
+	_, err = db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		archiveBlockHeader(tr, blockNumber, SerializeBlockHeader(newBlock.BlockHeader), blockHash[:])
+		return nil, nil
+	})
+	return err
+}
+
+func TestProofsMatchBlockMerkleRoot(t *testing.T) {
+	parser := transaction.NewTransactionParser(1)
+	for count := 1; count <= 7; count++ {
+		db := storage.NewMemoryDatabase()
+		blockNumber := uint32(count)
+		txs := []*transaction.SignedTransaction{}
+		for i := 0; i < count; i++ {
+			raw, err := createTestTransfer(1, i, 0, testAmount, testRecipient, testOwnerKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := parser.Parse(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			txs = append(txs, &parsed.TX)
+		}
+		newBlock, err := block.NewBlock(blockNumber, txs, make([]byte, block.PreviousBlockHashLength))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = newBlock.Sign(testOwnerKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root := newBlock.BlockHeader.MerkleTreeRoot[:]
+		tree, err := merkle.Tree(txs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(tree.MerkleRoot()) != string(root) {
+			t.Fatal("Merkle root differs from the block root for " + strconv.Itoa(count) + " transactions")
+		}
+		err = archiveTestBlock(db, blockNumber, newBlock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < count; i++ {
+			proof, err := GetTransactionProof(db, blockNumber, uint32(i))
+			if err != nil {
+				t.Fatal("No proof for transaction " + strconv.Itoa(i) + " of " + strconv.Itoa(count) + ": " + err.Error())
+			}
+			if !merkle.Verify(tree.Leafs[i].Hash, i, proof.Branch, root) {
+				t.Fatal("Proof for transaction " + strconv.Itoa(i) + " of " + strconv.Itoa(count) + " does not verify")
+			}
+		}
+	}
+}
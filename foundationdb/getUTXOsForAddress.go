@@ -3,19 +3,20 @@ package foundationdb
 import (
 	"encoding/binary"
 	"errors"
+	"math/big"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
 	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	commonConst "github.com/matterinc/PlasmaCommons/common"
 	transaction "github.com/matterinc/PlasmaCommons/transaction"
 	"github.com/matterinc/PlasmaCommons/types"
 )
 
 type UTXOlister struct {
-	db *fdb.Database
+	db storage.Database
 }
 
-func NewUTXOlister(db *fdb.Database) *UTXOlister {
+func NewUTXOlister(db storage.Database) *UTXOlister {
 	reader := &UTXOlister{db: db}
 	return reader
 }
@@ -110,16 +111,12 @@ func newExactUtxoRange(address common.Address, afterBlock uint32, afterTransacti
 }
 
 func (r *UTXOlister) GetUTXOsForAddress(address common.Address, afterBlock uint32, afterTransaction uint32, afterOutput uint8, limit int, showWithdrawn bool) ([][transaction.UTXOIndexLength]byte, error) {
-	options := fdb.RangeOptions{}
+	options := storage.RangeOptions{}
 	options.Limit = limit
 	readingRange, err := newUtxoRange(address, afterBlock, afterTransaction, afterOutput)
 	if err != nil {
 		return nil, err
 	}
-	pr, err := fdb.PrefixRange([]byte{})
-	if err != nil {
-		return nil, err
-	}
 	fullBeginingIndex := []byte{}
 	fullBeginingIndex = append(fullBeginingIndex, commonConst.UtxoIndexPrefix...)
 	fullBeginingIndex = append(fullBeginingIndex, readingRange.beginingKey...)
@@ -128,10 +125,9 @@ func (r *UTXOlister) GetUTXOsForAddress(address common.Address, afterBlock uint3
 	fullEndingIndex = append(fullEndingIndex, commonConst.UtxoIndexPrefix...)
 	fullEndingIndex = append(fullEndingIndex, readingRange.endingKey...)
 
-	pr.Begin = fdb.Key(fullBeginingIndex)
-	pr.End = fdb.Key(fullEndingIndex)
-	ret, err := r.db.ReadTransact(func(tr fdb.ReadTransaction) (interface{}, error) {
-		values, err := tr.GetRange(pr, options).GetSliceWithError()
+	pr := storage.KeyRange{Begin: fullBeginingIndex, End: fullEndingIndex}
+	ret, err := r.db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		values, err := tr.GetRange(pr, options)
 		if err != nil {
 			return nil, err
 		}
@@ -143,7 +139,7 @@ func (r *UTXOlister) GetUTXOsForAddress(address common.Address, afterBlock uint3
 	if ret == nil {
 		return nil, errors.New("Could not read utxos")
 	}
-	values := ret.([]fdb.KeyValue)
+	values := ret.([]storage.KeyValue)
 	toReturn := [][transaction.UTXOIndexLength]byte{}
 	expenctedKeyLength := len(commonConst.UtxoIndexPrefix) + transaction.UTXOIndexLength
 	toCutFromKey := len(commonConst.UtxoIndexPrefix)
@@ -172,16 +168,12 @@ func (r *UTXOlister) GetUTXOsForAddress(address common.Address, afterBlock uint3
 }
 
 func (r *UTXOlister) GetExactUTXOsForAddress(address common.Address, blockNumber uint32, transactionNumber uint32, outputNumber uint8, limit int, showWithdrawn bool) ([][transaction.UTXOIndexLength]byte, error) {
-	options := fdb.RangeOptions{}
+	options := storage.RangeOptions{}
 	options.Limit = limit
 	readingRange, err := newExactUtxoRange(address, blockNumber, transactionNumber, outputNumber)
 	if err != nil {
 		return nil, err
 	}
-	pr, err := fdb.PrefixRange([]byte{})
-	if err != nil {
-		return nil, err
-	}
 	fullBeginingIndex := []byte{}
 	fullBeginingIndex = append(fullBeginingIndex, commonConst.UtxoIndexPrefix...)
 	fullBeginingIndex = append(fullBeginingIndex, readingRange.beginingKey...)
@@ -190,10 +182,9 @@ func (r *UTXOlister) GetExactUTXOsForAddress(address common.Address, blockNumber
 	fullEndingIndex = append(fullEndingIndex, commonConst.UtxoIndexPrefix...)
 	fullEndingIndex = append(fullEndingIndex, readingRange.endingKey...)
 
-	pr.Begin = fdb.Key(fullBeginingIndex)
-	pr.End = fdb.Key(fullEndingIndex)
-	ret, err := r.db.ReadTransact(func(tr fdb.ReadTransaction) (interface{}, error) {
-		values, err := tr.GetRange(pr, options).GetSliceWithError()
+	pr := storage.KeyRange{Begin: fullBeginingIndex, End: fullEndingIndex}
+	ret, err := r.db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		values, err := tr.GetRange(pr, options)
 		if err != nil {
 			return nil, err
 		}
@@ -205,7 +196,7 @@ func (r *UTXOlister) GetExactUTXOsForAddress(address common.Address, blockNumber
 	if ret == nil {
 		return nil, errors.New("Could not read utxos")
 	}
-	values := ret.([]fdb.KeyValue)
+	values := ret.([]storage.KeyValue)
 	toReturn := [][transaction.UTXOIndexLength]byte{}
 	expenctedKeyLength := len(commonConst.UtxoIndexPrefix) + transaction.UTXOIndexLength
 	toCutFromKey := len(commonConst.UtxoIndexPrefix)
@@ -232,3 +223,119 @@ func (r *UTXOlister) GetExactUTXOsForAddress(address common.Address, blockNumber
 	}
 	return toReturn, nil
 }
+
+// UTXOState selects outputs by state when listing them
+type UTXOState int
+
+const (
+	UTXOStateSpendable UTXOState = iota
+	UTXOStateExitPending
+	UTXOStateAny
+)
+
+// ListedUTXO is an output with its full index, ExitPending is set for non-spendable outputs
+type ListedUTXO struct {
+	Index       [transaction.UTXOIndexLength]byte
+	ExitPending bool
+}
+
+// ListUTXOsForAddress returns up to limit outputs in the given state starting from the
+// given index, exclusive if it is after an already returned one. Pages are read until the
+// limit is reached or the address has no more outputs, hasMore tells whether there are more
+func (r *UTXOlister) ListUTXOsForAddress(address common.Address, from [transaction.UTXOIndexLength]byte, exclusive bool, state UTXOState, limit int) ([]ListedUTXO, bool, error) {
+	addressPrefix := []byte{}
+	addressPrefix = append(addressPrefix, commonConst.UtxoIndexPrefix...)
+	addressPrefix = append(addressPrefix, address[:]...)
+	addressRange, err := storage.PrefixRange(addressPrefix)
+	if err != nil {
+		return nil, false, err
+	}
+	fullBeginingIndex := []byte{}
+	fullBeginingIndex = append(fullBeginingIndex, commonConst.UtxoIndexPrefix...)
+	fullBeginingIndex = append(fullBeginingIndex, address[:]...)
+	fullBeginingIndex = append(fullBeginingIndex, from[transaction.AddressLength:]...)
+	if exclusive {
+		fullBeginingIndex = append(fullBeginingIndex, 0x00)
+	}
+
+	listed := []ListedUTXO{}
+	hasMore := false
+	expenctedKeyLength := len(commonConst.UtxoIndexPrefix) + transaction.UTXOIndexLength
+	toCutFromKey := len(commonConst.UtxoIndexPrefix)
+	pr := storage.KeyRange{Begin: fullBeginingIndex, End: addressRange.End}
+	// one more than the limit tells if there is a next page
+	err = storage.ReadPages(r.db, pr, limit+1, func(page []storage.KeyValue) error {
+		for _, kv := range page {
+			key := kv.Key
+			value := kv.Value
+			if len(value) != 1 || len(key) != expenctedKeyLength {
+				continue
+			}
+			exitPending := value[0] == commonConst.UTXOexistsButNotSpendable
+			if !exitPending && value[0] != commonConst.UTXOisReadyForSpending {
+				continue
+			}
+			if (state == UTXOStateSpendable && exitPending) || (state == UTXOStateExitPending && !exitPending) {
+				continue
+			}
+			if len(listed) == limit {
+				hasMore = true
+				return storage.ErrStopPages
+			}
+			utxo := ListedUTXO{ExitPending: exitPending}
+			copy(utxo.Index[:], key[toCutFromKey:])
+			listed = append(listed, utxo)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return listed, hasMore, nil
+}
+
+const balancePageSize = 1000
+
+type Balance struct {
+	Spendable   *big.Int
+	ExitPending *big.Int
+	Total       *big.Int
+}
+
+// GetBalanceForAddress sums values of all outputs of an address. Pages are read in separate
+// transactions at the same read version to stay within transaction time limits
+func (r *UTXOlister) GetBalanceForAddress(address common.Address) (*Balance, error) {
+	addressPrefix := []byte{}
+	addressPrefix = append(addressPrefix, commonConst.UtxoIndexPrefix...)
+	addressPrefix = append(addressPrefix, address[:]...)
+	addressRange, err := storage.PrefixRange(addressPrefix)
+	if err != nil {
+		return nil, err
+	}
+	balance := &Balance{big.NewInt(0), big.NewInt(0), big.NewInt(0)}
+	expenctedKeyLength := len(commonConst.UtxoIndexPrefix) + transaction.UTXOIndexLength
+	err = storage.ReadPages(r.db, addressRange, balancePageSize, func(page []storage.KeyValue) error {
+		for _, kv := range page {
+			key := kv.Key
+			value := kv.Value
+			if len(value) != 1 || len(key) != expenctedKeyLength {
+				continue
+			}
+			utxoValue := new(big.Int).SetBytes(key[len(key)-transaction.ValueLength:])
+			switch value[0] {
+			case commonConst.UTXOisReadyForSpending:
+				balance.Spendable.Add(balance.Spendable, utxoValue)
+			case commonConst.UTXOexistsButNotSpendable:
+				balance.ExitPending.Add(balance.ExitPending, utxoValue)
+			default:
+				continue
+			}
+			balance.Total.Add(balance.Total, utxoValue)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
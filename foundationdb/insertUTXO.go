@@ -4,16 +4,16 @@ import (
 	"bytes"
 	"errors"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	commonConst "github.com/matterinc/PlasmaCommons/common"
 	transaction "github.com/matterinc/PlasmaCommons/transaction"
 )
 
 type UTXOinserter struct {
-	db *fdb.Database
+	db storage.Database
 }
 
-func NewUTXOinserter(db *fdb.Database) *UTXOinserter {
+func NewUTXOinserter(db storage.Database) *UTXOinserter {
 	reader := &UTXOinserter{db: db}
 	return reader
 }
@@ -32,18 +32,18 @@ func (r *UTXOinserter) InsertUTXO(tx *transaction.SignedTransaction, blockNumber
 		utxoIndexes[i] = fullIndex
 	}
 
-	ret, err := r.db.Transact(func(tr fdb.Transaction) (interface{}, error) {
+	ret, err := r.db.Transact(func(tr storage.Transaction) (interface{}, error) {
 		for _, index := range utxoIndexes {
-			existing, err := tr.Get(fdb.Key(index)).Get()
+			existing, err := tr.Get(index).Get()
 			if err != nil || len(existing) != 0 {
 				return nil, err
 			}
 		}
 		for _, index := range utxoIndexes {
-			tr.Set(fdb.Key(index), []byte{commonConst.UTXOisReadyForSpending})
+			tr.Set(index, []byte{commonConst.UTXOisReadyForSpending})
 		}
 		for _, index := range utxoIndexes {
-			existing, err := tr.Get(fdb.Key(index)).Get()
+			existing, err := tr.Get(index).Get()
 			if err != nil {
 				tr.Reset()
 				return nil, err
@@ -6,7 +6,7 @@ import (
 
 	"github.com/matterinc/PlasmaCommons/transaction"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	commonConst "github.com/matterinc/PlasmaCommons/common"
 	types "github.com/matterinc/PlasmaCommons/types"
 )
@@ -16,7 +16,7 @@ type DepositLookupResult struct {
 	TransactionNumber int
 }
 
-func LookupDepositIndex(db *fdb.Database, index *types.BigInt) (*DepositLookupResult, error) {
+func LookupDepositIndex(db storage.Database, index *types.BigInt) (*DepositLookupResult, error) {
 	depositIndexKey := []byte{}
 	depositIndexKey = append(depositIndexKey, commonConst.DepositHistoryPrefix...)
 	depositIndexBytes, err := index.GetLeftPaddedBytes(32)
@@ -25,8 +25,8 @@ func LookupDepositIndex(db *fdb.Database, index *types.BigInt) (*DepositLookupRe
 	}
 	depositIndexKey = append(depositIndexKey, depositIndexBytes...)
 
-	result, err := db.ReadTransact(func(tr fdb.ReadTransaction) (interface{}, error) {
-		existing := tr.Get(fdb.Key(depositIndexKey)).MustGet()
+	result, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		existing := tr.Get(depositIndexKey).MustGet()
 		return existing, nil
 	})
 	if err != nil {
@@ -4,7 +4,7 @@ import (
 	"encoding/binary"
 	"fmt"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	commonConst "github.com/matterinc/PlasmaCommons/common"
 	"github.com/matterinc/PlasmaCommons/transaction"
 	types "github.com/matterinc/PlasmaCommons/types"
@@ -16,7 +16,7 @@ type SpendingLookupResult struct {
 	InputNumber       int
 }
 
-func LookupSpendingIndex(db *fdb.Database, index *types.BigInt) (*SpendingLookupResult, error) {
+func LookupSpendingIndex(db storage.Database, index *types.BigInt) (*SpendingLookupResult, error) {
 	details, err := transaction.ParseUTXOindexNumberIntoDetails(index)
 	if err != nil {
 		return nil, err
@@ -34,8 +34,8 @@ func LookupSpendingIndex(db *fdb.Database, index *types.BigInt) (*SpendingLookup
 	lookupIndex = append(lookupIndex, blockNumberBuffer[:]...)
 	lookupIndex = append(lookupIndex, transactionNumberBuffer[:]...)
 	lookupIndex = append(lookupIndex, outputNumberBuffer[:]...)
-	result, err := db.ReadTransact(func(tr fdb.ReadTransaction) (interface{}, error) {
-		existing := tr.Get(fdb.Key(lookupIndex)).MustGet()
+	result, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		existing := tr.Get(lookupIndex).MustGet()
 		return existing, nil
 	})
 	if err != nil {
@@ -6,18 +6,18 @@ import (
 	"errors"
 	"fmt"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	commonConst "github.com/matterinc/PlasmaCommons/common"
 	transaction "github.com/matterinc/PlasmaCommons/transaction"
 	types "github.com/matterinc/PlasmaCommons/types"
-	common "github.com/ethereum/go-ethereum/common"
 )
 
 type TestUTXOcreator struct {
-	db *fdb.Database
+	db storage.Database
 }
 
-func NewTestUTXOcreator(db *fdb.Database) *TestUTXOcreator {
+func NewTestUTXOcreator(db storage.Database) *TestUTXOcreator {
 	reader := &TestUTXOcreator{db: db}
 	return reader
 }
@@ -95,9 +95,9 @@ func (r *TestUTXOcreator) InsertUTXO(address common.Address, blockNumber uint32,
 	key = append(key, outputNumberBuffer...)
 	key = append(key, valueBuffer...)
 	utxoIndexes[0] = key
-	_, err = r.db.Transact(func(tr fdb.Transaction) (interface{}, error) {
+	_, err = r.db.Transact(func(tr storage.Transaction) (interface{}, error) {
 		for _, index := range utxoIndexes {
-			existing, err := tr.Get(fdb.Key(index)).Get()
+			existing, err := tr.Get(index).Get()
 			if err != nil {
 				return nil, err
 			}
@@ -106,10 +106,10 @@ func (r *TestUTXOcreator) InsertUTXO(address common.Address, blockNumber uint32,
 			}
 		}
 		for _, index := range utxoIndexes {
-			tr.Set(fdb.Key(index), []byte{commonConst.UTXOisReadyForSpending})
+			tr.Set(index, []byte{commonConst.UTXOisReadyForSpending})
 		}
 		for _, index := range utxoIndexes {
-			existing, err := tr.Get(fdb.Key(index)).Get()
+			existing, err := tr.Get(index).Get()
 			if err != nil {
 				tr.Reset()
 				return nil, err
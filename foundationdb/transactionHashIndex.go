@@ -0,0 +1,160 @@
+package foundationdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/matterinc/PlasmaBlockCreator/rejections"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	commonConst "github.com/matterinc/PlasmaCommons/common"
+	"github.com/matterinc/PlasmaCommons/transaction"
+)
+
+// TransactionHashIndexPrefix maps a transaction hash to a pending spending record or to a place in a written block.
+// Rejected transactions are kept in a rejections.Store instead
+var TransactionHashIndexPrefix = []byte("txhash")
+
+const (
+	transactionIsPending  = byte(0x01)
+	transactionIsIncluded = byte(0x02)
+)
+
+const TransactionHashLength = 32
+
+type TransactionLookupResult struct {
+	Pending           bool
+	Rejected          bool
+	RejectionReason   string
+	BlockNumber       uint32
+	TransactionNumber uint32
+	RawTransaction    []byte
+}
+
+// TransactionHash returns keccak256 of the RLP encoded signed transaction and the encoding itself
+func TransactionHash(tx *transaction.SignedTransaction) ([]byte, []byte, error) {
+	var b bytes.Buffer
+	err := tx.EncodeRLP(io.Writer(&b))
+	if err != nil {
+		return nil, nil, err
+	}
+	raw := b.Bytes()
+	return crypto.Keccak256(raw), raw, nil
+}
+
+func CreateTransactionHashIndex(hash []byte) []byte {
+	index := []byte{}
+	index = append(index, TransactionHashIndexPrefix...)
+	index = append(index, hash...)
+	return index
+}
+
+func createPendingTransactionRecord(transactionIndex []byte) []byte {
+	record := []byte{transactionIsPending}
+	record = append(record, transactionIndex...)
+	return record
+}
+
+func createIncludedTransactionRecord(blockNumber uint32, transactionNumber uint32, raw []byte) []byte {
+	record := make([]byte, 1+transaction.BlockNumberLength+transaction.TransactionNumberLength, 1+transaction.BlockNumberLength+transaction.TransactionNumberLength+len(raw))
+	record[0] = transactionIsIncluded
+	binary.BigEndian.PutUint32(record[1:], blockNumber)
+	binary.BigEndian.PutUint32(record[1+transaction.BlockNumberLength:], transactionNumber)
+	record = append(record, raw...)
+	return record
+}
+
+func LookupTransaction(db storage.Database, hash []byte) (*TransactionLookupResult, error) {
+	if len(hash) != TransactionHashLength {
+		return nil, errors.New("Invalid transaction hash")
+	}
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		record, err := tr.Get(CreateTransactionHashIndex(hash)).Get()
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 0 {
+			return nil, errors.New("Transaction not found")
+		}
+		switch record[0] {
+		case transactionIsIncluded:
+			if len(record) < 1+transaction.BlockNumberLength+transaction.TransactionNumberLength {
+				return nil, errors.New("Invalid transaction record")
+			}
+			blockNumber := binary.BigEndian.Uint32(record[1:])
+			transactionNumber := binary.BigEndian.Uint32(record[1+transaction.BlockNumberLength:])
+			raw := record[1+transaction.BlockNumberLength+transaction.TransactionNumberLength:]
+			return &TransactionLookupResult{BlockNumber: blockNumber, TransactionNumber: transactionNumber, RawTransaction: raw}, nil
+		case transactionIsPending:
+			transactionIndex := record[1:]
+			if len(transactionIndex) < len(commonConst.TransactionIndexPrefix)+transaction.BlockNumberLength {
+				return nil, errors.New("Invalid transaction record")
+			}
+			spendingRecordRaw, err := tr.Get(transactionIndex).Get()
+			if err != nil {
+				return nil, err
+			}
+			if len(spendingRecordRaw) == 0 {
+				return nil, errors.New("Spending record is missing")
+			}
+			var spendingRecord transaction.SpendingRecord
+			err = rlp.DecodeBytes(spendingRecordRaw, &spendingRecord)
+			if err != nil {
+				return nil, errors.New("Failed to deserialize spending record")
+			}
+			_, raw, err := TransactionHash(spendingRecord.SpendingTransaction)
+			if err != nil {
+				return nil, err
+			}
+			blockNumber := binary.BigEndian.Uint32(transactionIndex[len(commonConst.TransactionIndexPrefix):])
+			return &TransactionLookupResult{Pending: true, BlockNumber: blockNumber, RawTransaction: raw}, nil
+		default:
+			return nil, errors.New("Invalid transaction record")
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret.(*TransactionLookupResult), nil
+}
+
+// LookupTransactionByCounter finds a transaction by the counter it was given on submission
+func LookupTransactionByCounter(db storage.Database, counter uint64) (*TransactionLookupResult, error) {
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return tr.Get(CreateTransactionIndex(counter)).Get()
+	})
+	if err != nil {
+		return nil, err
+	}
+	spendingRecordRaw := ret.([]byte)
+	if len(spendingRecordRaw) == 0 {
+		return nil, errors.New("Transaction not found")
+	}
+	var spendingRecord transaction.SpendingRecord
+	err = rlp.DecodeBytes(spendingRecordRaw, &spendingRecord)
+	if err != nil {
+		return nil, errors.New("Failed to deserialize spending record")
+	}
+	hash, _, err := TransactionHash(spendingRecord.SpendingTransaction)
+	if err != nil {
+		return nil, err
+	}
+	return LookupTransaction(db, hash)
+}
+
+// LookupTransactionOrRejection falls back to recent rejections. A transaction that was
+// accepted is reported as such even if it was rejected when it was submitted again
+func LookupTransactionOrRejection(db storage.Database, rejected rejections.Store, hash []byte) (*TransactionLookupResult, error) {
+	result, err := LookupTransaction(db, hash)
+	if err == nil {
+		return result, nil
+	}
+	reason, ok, rejectionErr := rejected.Reason(hash)
+	if rejectionErr != nil || !ok {
+		return nil, err
+	}
+	return &TransactionLookupResult{Rejected: true, RejectionReason: reason}, nil
+}
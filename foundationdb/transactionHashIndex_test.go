@@ -0,0 +1,51 @@
+package foundationdb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/matterinc/PlasmaBlockCreator/rejections"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+)
+
+func TestRejectionDoesNotOverwriteAcceptedTransaction(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	rejected := rejections.NewMemoryStore(time.Hour, 10)
+	rejectedHash := bytes.Repeat([]byte{0x01}, TransactionHashLength)
+	err := rejected.Reject(rejectedHash, "Double spend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := LookupTransactionOrRejection(db, rejected, rejectedHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Rejected || result.RejectionReason != "Double spend" {
+		t.Fatal("Transaction should be reported as rejected")
+	}
+
+	includedHash := bytes.Repeat([]byte{0x02}, TransactionHashLength)
+	_, err = db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		tr.Set(CreateTransactionHashIndex(includedHash), createIncludedTransactionRecord(3, 4, []byte{0xc0}))
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rejected.Reject(includedHash, "Double spend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = LookupTransactionOrRejection(db, rejected, includedHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rejected || result.BlockNumber != 3 || result.TransactionNumber != 4 {
+		t.Fatal("Resubmission must not hide an included transaction")
+	}
+	_, err = LookupTransactionOrRejection(db, rejected, bytes.Repeat([]byte{0x03}, TransactionHashLength))
+	if err == nil {
+		t.Fatal("Unknown transaction should not be found")
+	}
+}
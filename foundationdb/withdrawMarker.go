@@ -3,19 +3,19 @@ package foundationdb
 import (
 	"errors"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
 	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	commonConst "github.com/matterinc/PlasmaCommons/common"
 	"github.com/matterinc/PlasmaCommons/transaction"
 	types "github.com/matterinc/PlasmaCommons/types"
 )
 
 type WithdrawTXMarker struct {
-	db     *fdb.Database
+	db     storage.Database
 	lister *UTXOlister
 }
 
-func NewWithdrawTXMarker(db *fdb.Database) *WithdrawTXMarker {
+func NewWithdrawTXMarker(db storage.Database) *WithdrawTXMarker {
 	lister := NewUTXOlister(db)
 	marker := &WithdrawTXMarker{db: db, lister: lister}
 	return marker
@@ -46,8 +46,8 @@ func (r *WithdrawTXMarker) MarkTX(to common.Address,
 	utxoIndex := []byte{}
 	utxoIndex = append(utxoIndex, commonConst.UtxoIndexPrefix...)
 	utxoIndex = append(utxoIndex, existingUTXO[0][:]...)
-	_, err = r.db.Transact(func(tr fdb.Transaction) (interface{}, error) {
-		existing := tr.Get(fdb.Key(utxoIndex)).MustGet()
+	_, err = r.db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		existing := tr.Get(utxoIndex).MustGet()
 		if len(existing) != 1 {
 			return nil, errors.New("Invalid UTXO state")
 		}
@@ -57,8 +57,8 @@ func (r *WithdrawTXMarker) MarkTX(to common.Address,
 		if existing[0] != commonConst.UTXOisReadyForSpending {
 			return nil, errors.New("Invalid UTXO state")
 		}
-		tr.Set(fdb.Key(utxoIndex), []byte{commonConst.UTXOexistsButNotSpendable})
-		existing = tr.Get(fdb.Key(utxoIndex)).MustGet()
+		tr.Set(utxoIndex, []byte{commonConst.UTXOexistsButNotSpendable})
+		existing = tr.Get(utxoIndex).MustGet()
 		if len(existing) != 1 {
 			return nil, errors.New("Invalid UTXO state")
 		}
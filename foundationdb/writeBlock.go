@@ -8,7 +8,7 @@ import (
 	"strconv"
 	"time"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	"github.com/matterinc/PlasmaCommons/block"
 	commonConst "github.com/matterinc/PlasmaCommons/common"
 	transaction "github.com/matterinc/PlasmaCommons/transaction"
@@ -17,10 +17,10 @@ import (
 const blockSliceLengthToWrite = 10000
 
 type BlockWriter struct {
-	db *fdb.Database
+	db storage.Database
 }
 
-func NewBlockWriter(db *fdb.Database) *BlockWriter {
+func NewBlockWriter(db storage.Database) *BlockWriter {
 	reader := &BlockWriter{db: db}
 	return reader
 }
@@ -46,6 +46,7 @@ func (r *BlockWriter) WriteBlock(block block.Block) error {
 	numberOfTransactionInBlock := len(block.Transactions)
 	utxosToWrite := make([][][]byte, numberOfTransactionInBlock)                // [numTxes][someOutputsPerTX][outputBytes]
 	spendingHistoriesToWrite := make([][][2][]byte, numberOfTransactionInBlock) //[numTxes][someInputsPerTX][originating, spending][data]
+	hashIndexesToWrite := make([][2][]byte, numberOfTransactionInBlock)         //[numTxes][hash index, record]
 	// inputLookupHashmap := hashmap.New(uintptr(numberOfTransactionInBlock))
 	// outputLookupHashmap := hashmap.New(uintptr(numberOfTransactionInBlock))
 
@@ -117,6 +118,11 @@ func (r *BlockWriter) WriteBlock(block block.Block) error {
 		}
 		utxosToWrite[i] = transactionNewUTXOs
 
+		hash, raw, err := TransactionHash(tx)
+		if err != nil {
+			return err
+		}
+		hashIndexesToWrite[i] = [2][]byte{CreateTransactionHashIndex(hash), createIncludedTransactionRecord(blockNumber, uint32(i), raw)}
 	}
 	elapsed := time.Since(start)
 	fmt.Println("Block writing preparation taken " + fmt.Sprintf("%d", elapsed.Nanoseconds()/1000000) + " ms")
@@ -141,7 +147,8 @@ func (r *BlockWriter) WriteBlock(block block.Block) error {
 		}
 		currentUTXOSlice := utxosToWrite[minTxNumber : maxTxNumber+1]
 		currentHistorySlice := spendingHistoriesToWrite[minTxNumber : maxTxNumber+1]
-		err := r.writeSlice(currentUTXOSlice, currentHistorySlice, blockNumber, minTxNumber, maxTxNumber)
+		currentHashIndexSlice := hashIndexesToWrite[minTxNumber : maxTxNumber+1]
+		err := r.writeSlice(currentUTXOSlice, currentHistorySlice, currentHashIndexSlice, blockNumber, minTxNumber, maxTxNumber)
 		if err != nil {
 			return err
 		}
@@ -149,9 +156,24 @@ func (r *BlockWriter) WriteBlock(block block.Block) error {
 	}
 	fmt.Println("Has written " + strconv.Itoa(totalWritten) + " transaction for outputs and histories")
 
-	_, err = r.db.Transact(func(tr fdb.Transaction) (interface{}, error) {
-		tr.Set(fdb.Key(commonConst.BlockNumberKey), block.BlockHeader.BlockNumber[:])
-		updateValue, err := tr.Get(fdb.Key(commonConst.BlockNumberKey)).Get()
+	rawBlock, err := block.Serialize()
+	if err != nil {
+		return err
+	}
+	blockHash, err := block.BlockHeader.GetHash()
+	if err != nil {
+		return err
+	}
+	err = writeBlockChunks(r.db, blockNumber, rawBlock)
+	if err != nil {
+		return err
+	}
+	rawHeader := SerializeBlockHeader(block.BlockHeader)
+
+	_, err = r.db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		archiveBlockHeader(tr, blockNumber, rawHeader, blockHash[:])
+		tr.Set(commonConst.BlockNumberKey, block.BlockHeader.BlockNumber[:])
+		updateValue, err := tr.Get(commonConst.BlockNumberKey).Get()
 		if err != nil {
 			return nil, err
 		}
@@ -169,7 +191,7 @@ func (r *BlockWriter) WriteBlock(block block.Block) error {
 	return nil
 }
 
-func (r *BlockWriter) writeSlice(utxoSlice [][][]byte, historySlice [][][2][]byte, blockNumber uint32, minTxNumber uint32, maxTxNumber uint32) error {
+func (r *BlockWriter) writeSlice(utxoSlice [][][]byte, historySlice [][][2][]byte, hashIndexSlice [][2][]byte, blockNumber uint32, minTxNumber uint32, maxTxNumber uint32) error {
 	bn, txn, err := GetLastWrittenTransactionAndBlock(r.db)
 	if err != nil {
 		return err
@@ -196,31 +218,35 @@ func (r *BlockWriter) writeSlice(utxoSlice [][][]byte, historySlice [][][2][]byt
 	transactionNumberBuffer := make([]byte, transaction.TransactionNumberLength)
 	binary.BigEndian.PutUint32(transactionNumberBuffer, maxTxNumber)
 
-	futureUTXOSlices := []fdb.FutureByteSlice{}
-	futureHistorySlices := []fdb.FutureByteSlice{}
+	futureUTXOSlices := []storage.FutureValue{}
+	futureHistorySlices := []storage.FutureValue{}
 	newLastTxIndex := []byte{}
 	newLastTxIndex = append(newLastTxIndex, blockNumberBuffer...)
 	newLastTxIndex = append(newLastTxIndex, transactionNumberBuffer...)
 	// i := 0
 	// j := 0
 
-	_, err = r.db.Transact(func(tr fdb.Transaction) (interface{}, error) {
+	_, err = r.db.Transact(func(tr storage.Transaction) (interface{}, error) {
 		for _, transactionUTXOs := range utxoSlice {
 			for _, utxo := range transactionUTXOs {
-				tr.Set(fdb.Key(utxo), []byte{commonConst.UTXOisReadyForSpending})
-				futureUTXOSlices = append(futureUTXOSlices, tr.Get(fdb.Key(utxo)))
+				tr.Set(utxo, []byte{commonConst.UTXOisReadyForSpending})
+				futureUTXOSlices = append(futureUTXOSlices, tr.Get(utxo))
 			}
 		}
 
 		for _, transactionHistories := range historySlice {
 			for _, history := range transactionHistories {
-				tr.Set(fdb.Key(history[0]), history[1])
-				futureHistorySlices = append(futureHistorySlices, tr.Get(fdb.Key(history[0])))
+				tr.Set(history[0], history[1])
+				futureHistorySlices = append(futureHistorySlices, tr.Get(history[0]))
 			}
 		}
 
-		tr.Set(fdb.Key(commonConst.TransactionNumberKey), newLastTxIndex)
-		futureIndexRec := tr.Get(fdb.Key(commonConst.TransactionNumberKey))
+		for _, hashIndex := range hashIndexSlice {
+			tr.Set(hashIndex[0], hashIndex[1])
+		}
+
+		tr.Set(commonConst.TransactionNumberKey, newLastTxIndex)
+		futureIndexRec := tr.Get(commonConst.TransactionNumberKey)
 
 		// for _, transactionUTXOs := range utxoSlice {
 		// 	for range transactionUTXOs {
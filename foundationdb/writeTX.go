@@ -4,17 +4,24 @@ import (
 	"bytes"
 	"errors"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
+	"github.com/matterinc/PlasmaBlockCreator/sequencer"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	"github.com/matterinc/PlasmaCommons/transaction"
 )
 
 type UTXOWriter struct {
-	db                 *fdb.Database
+	db                 storage.Database
 	Concurrency        int
 	concurrencyChannel chan bool
 }
 
-func NewUTXOWriter(db *fdb.Database, concurrency int) *UTXOWriter {
+// ErrDoubleSpend is returned when one of the inputs was already spent by another transaction
+var ErrDoubleSpend = errors.New("Double spend")
+
+// ErrTransactionExists is returned when a counter was already used for another transaction
+var ErrTransactionExists = errors.New("Such transaction already exists")
+
+func NewUTXOWriter(db storage.Database, concurrency int) *UTXOWriter {
 	c := make(chan bool, concurrency)
 	reader := &UTXOWriter{db: db, Concurrency: concurrency, concurrencyChannel: c}
 	return reader
@@ -24,27 +31,32 @@ func (r *UTXOWriter) WriteSpending(res *transaction.ParsedTransactionResult, cou
 	r.concurrencyChannel <- true
 	defer func() { <-r.concurrencyChannel }()
 	transactionIndex := CreateTransactionIndex(counter)
-	futureSlices := make([]fdb.FutureByteSlice, len(res.UtxoIndexes))
-	// _, err := Transact(func(tr fdb.Transaction) (interface{}, error) {
-	_, err := r.db.Transact(func(tr fdb.Transaction) (interface{}, error) {
+	hash, _, err := TransactionHash(&res.TX)
+	if err != nil {
+		return err
+	}
+	hashIndex := CreateTransactionHashIndex(hash)
+	futureSlices := make([]storage.FutureValue, len(res.UtxoIndexes))
+	_, err = r.db.Transact(func(tr storage.Transaction) (interface{}, error) {
 		// tr.AddWriteConflictKey(fdb.Key(transactionIndex))
 		for i, utxoIndex := range res.UtxoIndexes {
-			futureSlices[i] = tr.Get(fdb.Key(utxoIndex.Key))
+			futureSlices[i] = tr.Get(utxoIndex.Key)
 		}
-		futureTxRec := tr.Get(fdb.Key(transactionIndex))
+		futureTxRec := tr.Get(transactionIndex)
 		for i, utxoIndex := range res.UtxoIndexes {
 			valueRead := futureSlices[i].MustGet()
 			if bytes.Compare(valueRead, utxoIndex.Value) != 0 {
-				return nil, errors.New("Double spend")
+				return nil, ErrDoubleSpend
 			}
 		}
 		if len(futureTxRec.MustGet()) != 0 {
-			return nil, errors.New("Such transaction already exists")
+			return nil, ErrTransactionExists
 		}
 		for _, utxoIndex := range res.UtxoIndexes {
-			tr.Clear(fdb.Key(utxoIndex.Key))
+			tr.Clear(utxoIndex.Key)
 		}
-		tr.Set(fdb.Key(transactionIndex), res.SpendingRecord)
+		tr.Set(transactionIndex, res.SpendingRecord)
+		tr.Set(hashIndex, createPendingTransactionRecord(transactionIndex))
 		// tr.ByteMax(fdb.Key(transactionIndex), res.SpendingRecord)
 		return nil, nil
 	})
@@ -54,3 +66,42 @@ func (r *UTXOWriter) WriteSpending(res *transaction.ParsedTransactionResult, cou
 	}
 	return nil
 }
+
+// WriteSpendingInOpenBlock puts a spending record into the block that is currently open
+// for new transactions, ordered by the commit versionstamp instead of a counter
+func (r *UTXOWriter) WriteSpendingInOpenBlock(res *transaction.ParsedTransactionResult) error {
+	r.concurrencyChannel <- true
+	defer func() { <-r.concurrencyChannel }()
+	hash, _, err := TransactionHash(&res.TX)
+	if err != nil {
+		return err
+	}
+	hashIndex := CreateTransactionHashIndex(hash)
+	futureSlices := make([]storage.FutureValue, len(res.UtxoIndexes))
+	_, err = r.db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		for i, utxoIndex := range res.UtxoIndexes {
+			futureSlices[i] = tr.Get(utxoIndex.Key)
+		}
+		blockNumber, err := sequencer.ReadBlockEpoch(tr)
+		if err != nil {
+			return nil, err
+		}
+		for i, utxoIndex := range res.UtxoIndexes {
+			valueRead := futureSlices[i].MustGet()
+			if bytes.Compare(valueRead, utxoIndex.Value) != 0 {
+				return nil, ErrDoubleSpend
+			}
+		}
+		for _, utxoIndex := range res.UtxoIndexes {
+			tr.Clear(utxoIndex.Key)
+		}
+		transactionIndex, offset := CreateVersionstampedTransactionIndex(blockNumber)
+		tr.SetVersionstampedKey(transactionIndex, offset, res.SpendingRecord)
+		tr.SetVersionstampedValue(hashIndex, createPendingTransactionRecord(transactionIndex), 1+offset)
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -4,13 +4,15 @@ import (
 	"encoding/json"
 	"strconv"
 
+	"github.com/matterinc/PlasmaBlockCreator/router"
 	"github.com/matterinc/PlasmaCommons/block"
 	"github.com/valyala/fasthttp"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
 	common "github.com/ethereum/go-ethereum/common"
-	redis "github.com/go-redis/redis"
 	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/sequencer"
+	"github.com/matterinc/PlasmaBlockCreator/signer"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 )
 
 type assmebleBlockRequest struct {
@@ -25,16 +27,22 @@ type assembleBlockResponse struct {
 	SerializedBlock string `json:"serializedBlock,omitempty"`
 }
 
+var (
+	errAssemblyFailed = apiError{"assembly_failed", "failed to assemble block", fasthttp.StatusConflict, false, 0}
+	errSigningFailed  = apiError{"signing_failed", "failed to sign block", fasthttp.StatusInternalServerError, false, 0}
+	errInternal       = apiError{"internal_error", "failed to serialize block", fasthttp.StatusInternalServerError, false, 0}
+)
+
 type AssembleBlockHandler struct {
-	db             *fdb.Database
-	redisClient    *redis.Client
+	db             storage.Database
+	sequencer      sequencer.Sequencer
 	blockAssembler *foundationdb.BlockAssembler
-	signingKey     []byte
+	signer         signer.Signer
 }
 
-func NewAssembleBlockHandler(db *fdb.Database, redisClient *redis.Client, signingKey []byte) *AssembleBlockHandler {
-	creator := foundationdb.NewBlockAssembler(db, redisClient)
-	handler := &AssembleBlockHandler{db, redisClient, creator, signingKey}
+func NewAssembleBlockHandler(db storage.Database, sequencer sequencer.Sequencer, signer signer.Signer) *AssembleBlockHandler {
+	creator := foundationdb.NewBlockAssembler(db, sequencer)
+	handler := &AssembleBlockHandler{db, sequencer, creator, signer}
 	return handler
 }
 
@@ -42,31 +50,25 @@ func (h *AssembleBlockHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
 	var requestJSON assmebleBlockRequest
 	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
 	if err != nil {
-		writeBlockAssemblyResponse(ctx, true, []byte{})
+		writeInvalidRequest(ctx, "invalid request")
 		return
 	}
 	previousHash := common.FromHex(requestJSON.PreviousBlockHash)
 	if len(previousHash) != block.PreviousBlockHashLength {
-		writeBlockAssemblyResponse(ctx, true, []byte{})
+		writeInvalidRequest(ctx, "invalid previous block hash")
 		return
 	}
 	// newBlockNumber := uint32(requestJSON.BlockNumber)
-	bn, _ := strconv.ParseUint(requestJSON.BlockNumber, 10, 32)
-	newBlockNumber := uint32(bn)
-	startNext := requestJSON.StartNext
-	block, err := h.blockAssembler.AssembleBlock(newBlockNumber, previousHash, startNext)
-	if err != nil || block == nil {
-		writeBlockAssemblyResponse(ctx, true, []byte{})
-		return
-	}
-	err = block.Sign(h.signingKey)
+	bn, err := strconv.ParseUint(requestJSON.BlockNumber, 10, 32)
 	if err != nil {
-		writeBlockAssemblyResponse(ctx, true, []byte{})
+		writeInvalidRequest(ctx, "invalid block number")
 		return
 	}
-	rawBlock, err := block.Serialize()
-	if err != nil || rawBlock == nil {
-		writeBlockAssemblyResponse(ctx, true, []byte{})
+	newBlockNumber := uint32(bn)
+	startNext := requestJSON.StartNext
+	_, rawBlock, failure := h.assemble(newBlockNumber, previousHash, startNext)
+	if failure != nil {
+		writeAPIError(ctx, failure)
 		return
 	}
 	writeBlockAssemblyResponse(ctx, false, rawBlock)
@@ -78,10 +80,24 @@ func (h *AssembleBlockHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
 	return
 }
 
+// assemble closes a block and returns it signed, an empty block is not assembled
+func (h *AssembleBlockHandler) assemble(blockNumber uint32, previousHash []byte, startNext bool) (*block.Block, []byte, *apiError) {
+	newBlock, err := h.blockAssembler.AssembleBlock(blockNumber, previousHash, startNext)
+	if err != nil || newBlock == nil {
+		return nil, nil, &errAssemblyFailed
+	}
+	err = h.signer.SignBlock(newBlock)
+	if err != nil {
+		return nil, nil, &errSigningFailed
+	}
+	rawBlock, err := newBlock.Serialize()
+	if err != nil || rawBlock == nil {
+		return nil, nil, &errInternal
+	}
+	return newBlock, rawBlock, nil
+}
+
 func writeBlockAssemblyResponse(ctx *fasthttp.RequestCtx, errorResult bool, rawBlock []byte) {
 	response := assembleBlockResponse{Error: errorResult, SerializedBlock: common.ToHex(rawBlock)}
-	ctx.SetContentType("application/json")
-	ctx.SetStatusCode(fasthttp.StatusOK)
-	body, _ := json.Marshal(response)
-	ctx.SetBody(body)
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
 }
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+)
+
+// BlockSchedulerPollInterval is how often the scheduler checks its triggers
+const BlockSchedulerPollInterval = time.Second
+
+// BlockScheduler produces blocks without an outside caller. The next block number and
+// the previous hash are taken from the last written block, a block is assembled, signed
+// and written once interval has passed since the last attempt or once threshold
+// transactions are pending. Empty blocks are skipped
+type BlockScheduler struct {
+	db        storage.Database
+	assembler *AssembleBlockHandler
+	writer    *WriteBlockHandler
+	interval  time.Duration
+	threshold int
+	// mutex keeps a block from being produced twice at once
+	mutex       sync.Mutex
+	lastAttempt time.Time
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+// NewBlockScheduler returns nil if neither trigger is set, blocks are assembled over HTTP then
+func NewBlockScheduler(db storage.Database, assembler *AssembleBlockHandler, writer *WriteBlockHandler, interval time.Duration, threshold int) *BlockScheduler {
+	if interval <= 0 && threshold <= 0 {
+		return nil
+	}
+	scheduler := &BlockScheduler{db: db,
+		assembler: assembler,
+		writer:    writer,
+		interval:  interval,
+		threshold: threshold,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{})}
+	return scheduler
+}
+
+// Start runs the production loop until Stop is called
+func (s *BlockScheduler) Start() {
+	s.lastAttempt = time.Now()
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(BlockSchedulerPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.tick()
+			}
+		}
+	}()
+}
+
+// Stop waits for a block that is being produced
+func (s *BlockScheduler) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+func (s *BlockScheduler) tick() {
+	due := s.interval > 0 && time.Since(s.lastAttempt) >= s.interval
+	if !due && s.threshold > 0 {
+		blockNumber, _, err := foundationdb.NextBlockToAssemble(s.db)
+		if err != nil {
+			fmt.Println("Failed to find the next block: " + err.Error())
+			return
+		}
+		pending, err := foundationdb.CountPendingTransactions(s.db, blockNumber, s.threshold)
+		if err != nil {
+			fmt.Println("Failed to count pending transactions: " + err.Error())
+			return
+		}
+		due = pending >= s.threshold
+	}
+	if !due {
+		return
+	}
+	s.lastAttempt = time.Now()
+	blockNumber, produced, err := s.ProduceBlock()
+	if err != nil {
+		fmt.Println("Failed to produce block " + strconv.Itoa(int(blockNumber)) + ": " + err.Error())
+		return
+	}
+	if produced {
+		fmt.Println("Produced block " + strconv.Itoa(int(blockNumber)))
+	}
+}
+
+// ProduceBlock assembles, signs and writes the next block. It returns false if there
+// were no pending transactions
+func (s *BlockScheduler) ProduceBlock() (uint32, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	blockNumber, previousHash, err := foundationdb.NextBlockToAssemble(s.db)
+	if err != nil {
+		return 0, false, err
+	}
+	pending, err := foundationdb.CountPendingTransactions(s.db, blockNumber, 1)
+	if err != nil {
+		return blockNumber, false, err
+	}
+	if pending == 0 {
+		return blockNumber, false, nil
+	}
+	newBlock, _, failure := s.assembler.assemble(blockNumber, previousHash, true)
+	if failure != nil {
+		return blockNumber, false, errors.New(failure.Message)
+	}
+	err = s.writer.write(newBlock)
+	if err != nil {
+		return blockNumber, false, err
+	}
+	return blockNumber, true, nil
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/events"
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/rejections"
+	"github.com/matterinc/PlasmaBlockCreator/sequencer"
+	"github.com/matterinc/PlasmaBlockCreator/signer"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	transaction "github.com/matterinc/PlasmaCommons/transaction"
+	"github.com/matterinc/PlasmaCommons/types"
+)
+
+func TestBlockSchedulerSkipsEmptyBlocks(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	if NewBlockScheduler(db, nil, nil, 0, 0) != nil {
+		t.Fatal("Scheduler without triggers should be disabled")
+	}
+	scheduler := NewBlockScheduler(db, NewAssembleBlockHandler(db, nil, nil), NewWriteBlockHandler(db, nil), 0, 10)
+	blockNumber, produced, err := scheduler.ProduceBlock()
+	if err != nil || produced || blockNumber != 1 {
+		t.Fatal("Empty block should not be produced")
+	}
+	scheduler.Start()
+	scheduler.Stop()
+}
+
+func TestBlockSchedulerChainsBlocks(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	seq, err := sequencer.NewLocalSequencer(db, sequencer.BlockLimits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockSigner, err := signer.NewKeySigner(testOwnerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendRawTX := NewSendRawTXHandler(db, seq, transaction.NewTransactionParser(1), 1, events.Discard,
+		rejections.NewMemoryStore(time.Hour, 10))
+	scheduler := NewBlockScheduler(db, NewAssembleBlockHandler(db, seq, blockSigner),
+		NewWriteBlockHandler(db, events.Discard), 0, 1)
+	// outputs of blocks that are not written yet fund both blocks
+	fundingBlocks := []int64{10, 11}
+	for i, fundingBlock := range fundingBlocks {
+		expected := uint32(i + 1)
+		err = foundationdb.NewTestUTXOcreator(db).InsertUTXO(testOwner, uint32(fundingBlock), 0, 0, types.NewBigInt(1000))
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := createTestSpend(fundingBlock, 1000, common.HexToAddress("0xf17f52151ebef6c7334fad080c5704d77216b732"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, failure := sendRawTX.submit("", common.FromHex(raw))
+		if failure != nil {
+			t.Fatal(failure.Message)
+		}
+		_, previousHash, err := foundationdb.NextBlockToAssemble(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blockNumber, produced, err := scheduler.ProduceBlock()
+		if err != nil || !produced || blockNumber != expected {
+			t.Fatal("Block with a pending transaction should be produced")
+		}
+		header, err := foundationdb.GetArchivedBlockHeader(db, blockNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.NumberOfTransactions != 1 || !bytes.Equal(header.ParentHash, previousHash) {
+			t.Fatal("Block should hold the transaction and point to the previous block")
+		}
+		if _, err = foundationdb.GetArchivedBlock(db, blockNumber); err != nil {
+			t.Fatal("Block should be archived")
+		}
+		next, nextPreviousHash, err := foundationdb.NextBlockToAssemble(db)
+		if err != nil || next != blockNumber+1 || !bytes.Equal(nextPreviousHash, header.Hash) {
+			t.Fatal("Next block should follow the produced one")
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/valyala/fasthttp"
+)
+
+// CBORContentType is answered instead of JSON when a client accepts it
+const CBORContentType = "application/cbor"
+
+// RawTransactionContentType is a request body with RLP bytes as they are, without JSON and hex
+const RawTransactionContentType = "application/octet-stream"
+
+// CBOR major types, see RFC 7049
+const (
+	cborUnsignedInteger = 0
+	cborByteString      = 2
+	cborTextString      = 3
+	cborMap             = 5
+	cborSimpleValue     = 7
+)
+
+const (
+	cborFalse = 20
+	cborTrue  = 21
+)
+
+// cborWriter encodes the few CBOR types that responses need
+type cborWriter struct {
+	buf []byte
+}
+
+func (w *cborWriter) head(major byte, n uint64) {
+	switch {
+	case n < 24:
+		w.buf = append(w.buf, major<<5|byte(n))
+	case n <= 0xff:
+		w.buf = append(w.buf, major<<5|24, byte(n))
+	case n <= 0xffff:
+		w.buf = append(w.buf, major<<5|25, 0, 0)
+		binary.BigEndian.PutUint16(w.buf[len(w.buf)-2:], uint16(n))
+	case n <= 0xffffffff:
+		w.buf = append(w.buf, major<<5|26, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(w.buf[len(w.buf)-4:], uint32(n))
+	default:
+		w.buf = append(w.buf, major<<5|27, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(w.buf[len(w.buf)-8:], n)
+	}
+}
+
+func (w *cborWriter) mapHeader(entries int) {
+	w.head(cborMap, uint64(entries))
+}
+
+func (w *cborWriter) text(s string) {
+	w.head(cborTextString, uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *cborWriter) bytes(b []byte) {
+	w.head(cborByteString, uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *cborWriter) uint(n uint64) {
+	w.head(cborUnsignedInteger, n)
+}
+
+func (w *cborWriter) bool(b bool) {
+	if b {
+		w.buf = append(w.buf, cborSimpleValue<<5|cborTrue)
+		return
+	}
+	w.buf = append(w.buf, cborSimpleValue<<5|cborFalse)
+}
+
+func acceptsCBOR(ctx *fasthttp.RequestCtx) bool {
+	return bytes.Contains(ctx.Request.Header.Peek("Accept"), []byte(CBORContentType))
+}
+
+func hasRawTransactionBody(ctx *fasthttp.RequestCtx) bool {
+	return bytes.HasPrefix(ctx.Request.Header.ContentType(), []byte(RawTransactionContentType))
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestSendRawTXCBORResponse(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+	writeSendRawTXCBORResponse(ctx, []byte{0xab, 0xcd}, 4294967297, nil)
+	expected := []byte{0xa4,
+		0x65, 'e', 'r', 'r', 'o', 'r', 0xf4,
+		0x68, 'a', 'c', 'c', 'e', 'p', 't', 'e', 'd', 0xf5,
+		0x64, 'h', 'a', 's', 'h', 0x42, 0xab, 0xcd,
+		0x67, 'c', 'o', 'u', 'n', 't', 'e', 'r', 0x1b, 0, 0, 0, 1, 0, 0, 0, 1}
+	if !bytes.Equal(ctx.Response.Body(), expected) {
+		t.Fatalf("Unexpected encoding %x", ctx.Response.Body())
+	}
+	if string(ctx.Response.Header.ContentType()) != CBORContentType {
+		t.Fatal("Content type should be CBOR")
+	}
+}
+
+func TestSendRawTXBinaryRequest(t *testing.T) {
+	h := &SendRawTXHandler{}
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetContentType(RawTransactionContentType)
+	ctx.Request.Header.Set("Accept", CBORContentType)
+	h.HandlerFunc(ctx)
+	if ctx.Response.StatusCode() != fasthttp.StatusBadRequest || string(ctx.Response.Header.ContentType()) != CBORContentType {
+		t.Fatal("Empty body should be rejected in CBOR")
+	}
+	if !bytes.Contains(ctx.Response.Body(), []byte("invalid_encoding")) {
+		t.Fatal("Empty body should be an invalid encoding")
+	}
+}
@@ -3,16 +3,22 @@ package handlers
 import (
 	"encoding/json"
 
+	"github.com/matterinc/PlasmaBlockCreator/router"
 	"github.com/matterinc/PlasmaCommons/transaction"
 	"github.com/matterinc/PlasmaCommons/types"
 	"github.com/valyala/fasthttp"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
 	common "github.com/ethereum/go-ethereum/common"
-	redis "github.com/go-redis/redis"
 	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/sequencer"
+	"github.com/matterinc/PlasmaBlockCreator/signer"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 )
 
+// fundingTransactionSize bounds the encoded size of a funding transaction with its single
+// input and output, it is counted against the block size limit before the transaction is built
+const fundingTransactionSize = 256
+
 type createFundingTXrequest struct {
 	For          string `json:"_from"`
 	DepositIndex string `json:"_depositIndex"`
@@ -24,14 +30,14 @@ type createFundingTXresponse struct {
 }
 
 type CreateFundingTXHandler struct {
-	db          *fdb.Database
-	redisClient *redis.Client
-	txCreator   *foundationdb.FundingTXcreator
+	db        storage.Database
+	sequencer sequencer.Sequencer
+	txCreator *foundationdb.FundingTXcreator
 }
 
-func NewCreateFundingTXHandler(db *fdb.Database, redisClient *redis.Client, signingKey []byte) *CreateFundingTXHandler {
-	creator := foundationdb.NewFundingTXcreator(db, signingKey)
-	handler := &CreateFundingTXHandler{db, redisClient, creator}
+func NewCreateFundingTXHandler(db storage.Database, sequencer sequencer.Sequencer, signer signer.Signer) *CreateFundingTXHandler {
+	creator := foundationdb.NewFundingTXcreator(db, signer)
+	handler := &CreateFundingTXHandler{db, sequencer, creator}
 	return handler
 }
 
@@ -39,32 +45,43 @@ func (h *CreateFundingTXHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
 	var requestJSON createFundingTXrequest
 	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
 	if err != nil {
-		writeDepositResponse(ctx, true)
+		writeInvalidRequest(ctx, "invalid request")
 		return
 	}
 	to := common.Address{}
 	toBytes := common.FromHex(requestJSON.For)
 	if len(toBytes) != transaction.AddressLength {
-		writeDepositResponse(ctx, true)
+		writeInvalidRequest(ctx, "invalid address")
 		return
 	}
 	copy(to[:], toBytes)
-	depositIndex := types.NewBigInt(0)
-	depositIndex.SetString(requestJSON.DepositIndex, 10)
-	value := types.NewBigInt(0)
-	value.SetString(requestJSON.Value, 10)
-	counter, err := h.redisClient.Incr("ctr").Result()
-	if err != nil {
-		writeDepositResponse(ctx, true)
+	depositIndex, ok := parseUnsignedBigInt(requestJSON.DepositIndex)
+	if !ok {
+		writeInvalidRequest(ctx, "invalid deposit index")
 		return
 	}
-	err = h.txCreator.CreateFundingTX(to, value, uint64(counter), depositIndex)
+	value, ok := parseUnsignedBigInt(requestJSON.Value)
+	if !ok {
+		writeInvalidRequest(ctx, "invalid amount")
+		return
+	}
+	if sequencer.AssignedOnCommit(h.sequencer) {
+		err = h.txCreator.CreateFundingTXInOpenBlock(to, value, depositIndex)
+	} else {
+		var counter uint64
+		counter, err = h.sequencer.Next(fundingTransactionSize)
+		if err != nil {
+			router.WriteError(ctx, fasthttp.StatusServiceUnavailable, "sequencer_unavailable", "failed to assign a transaction counter")
+			return
+		}
+		err = h.txCreator.CreateFundingTX(to, value, counter, depositIndex)
+	}
 	if err != nil {
 		if err.Error() == "Duplicate funding transaction" {
 			writeDepositResponse(ctx, false)
 			return
 		}
-		writeDepositResponse(ctx, true)
+		writeStorageUnavailable(ctx, "failed to create funding transaction")
 		return
 	}
 	writeDepositResponse(ctx, false)
@@ -73,8 +90,14 @@ func (h *CreateFundingTXHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
 
 func writeDepositResponse(ctx *fasthttp.RequestCtx, errorResult bool) {
 	response := createFundingTXresponse{errorResult}
-	ctx.SetContentType("application/json")
-	ctx.SetStatusCode(fasthttp.StatusOK)
-	body, _ := json.Marshal(response)
-	ctx.SetBody(body)
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
+}
+
+// parseUnsignedBigInt accepts decimal numbers only, SetString alone would leave zero on failure
+func parseUnsignedBigInt(s string) (*types.BigInt, bool) {
+	number, ok := types.NewBigInt(0).SetString(s, 10)
+	if !ok || number.Bigint.Sign() < 0 {
+		return nil, false
+	}
+	return number, true
 }
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/matterinc/PlasmaCommons/transaction"
+	"github.com/valyala/fasthttp"
+)
+
+type getBalanceRequest struct {
+	For string `json:"for"`
+}
+
+type balanceDetails struct {
+	Spendable   string `json:"spendable,omitempty"`
+	ExitPending string `json:"exitPending,omitempty"`
+	Total       string `json:"total,omitempty"`
+}
+
+type getBalanceResponse struct {
+	Error bool `json:"error"`
+	balanceDetails
+}
+
+type GetBalanceHandler struct {
+	db         storage.Database
+	utxoLister *foundationdb.UTXOlister
+}
+
+func NewGetBalanceHandler(db storage.Database) *GetBalanceHandler {
+	lister := foundationdb.NewUTXOlister(db)
+	handler := &GetBalanceHandler{db, lister}
+	return handler
+}
+
+func (h *GetBalanceHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
+	var requestJSON getBalanceRequest
+	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
+	if err != nil {
+		writeInvalidRequest(ctx, "invalid request")
+		return
+	}
+	details, failure := h.balance(requestJSON.For)
+	if failure != nil {
+		writeAPIError(ctx, failure)
+		return
+	}
+	writeGetBalanceResponse(ctx, getBalanceResponse{Error: false, balanceDetails: *details})
+	return
+}
+
+func (h *GetBalanceHandler) balance(forAddress string) (*balanceDetails, *apiError) {
+	forBytes := common.FromHex(forAddress)
+	if len(forBytes) != transaction.AddressLength {
+		return nil, invalidRequest("invalid address")
+	}
+	address := common.Address{}
+	copy(address[:], forBytes)
+	balance, err := h.utxoLister.GetBalanceForAddress(address)
+	if err != nil {
+		return nil, storageUnavailable("failed to read balance")
+	}
+	details := &balanceDetails{Spendable: balance.Spendable.String(),
+		ExitPending: balance.ExitPending.String(), Total: balance.Total.String()}
+	return details, nil
+}
+
+func writeGetBalanceResponse(ctx *fasthttp.RequestCtx, response getBalanceResponse) {
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
+}
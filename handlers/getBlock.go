@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/valyala/fasthttp"
+)
+
+// getBlockRequest selects a block by hash if it is given, by number otherwise
+type getBlockRequest struct {
+	BlockNumber int    `json:"blockNumber"`
+	Hash        string `json:"hash"`
+}
+
+type blockDetails struct {
+	BlockNumber int    `json:"blockNumber,omitempty"`
+	Hash        string `json:"hash,omitempty"`
+	Block       string `json:"block,omitempty"`
+}
+
+type getBlockResponse struct {
+	Error bool `json:"error"`
+	blockDetails
+}
+
+type blockHeaderDetails struct {
+	BlockNumber          int    `json:"blockNumber,omitempty"`
+	NumberOfTransactions int    `json:"numberOfTransactions,omitempty"`
+	ParentHash           string `json:"parentHash,omitempty"`
+	MerkleTreeRoot       string `json:"merkleTreeRoot,omitempty"`
+	Hash                 string `json:"hash,omitempty"`
+	Header               string `json:"header,omitempty"`
+}
+
+type getBlockHeaderResponse struct {
+	Error bool `json:"error"`
+	blockHeaderDetails
+}
+
+type GetBlockHandler struct {
+	db storage.Database
+}
+
+func NewGetBlockHandler(db storage.Database) *GetBlockHandler {
+	handler := &GetBlockHandler{db}
+	return handler
+}
+
+func (h *GetBlockHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
+	var requestJSON getBlockRequest
+	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
+	if err != nil {
+		writeInvalidRequest(ctx, "invalid request")
+		return
+	}
+	details, failure := findBlock(h.db, requestJSON.BlockNumber, common.FromHex(requestJSON.Hash))
+	if failure != nil {
+		writeAPIError(ctx, failure)
+		return
+	}
+	writeGetBlockResponse(ctx, getBlockResponse{Error: false, blockDetails: *details})
+	return
+}
+
+type GetBlockHeaderHandler struct {
+	db storage.Database
+}
+
+func NewGetBlockHeaderHandler(db storage.Database) *GetBlockHeaderHandler {
+	handler := &GetBlockHeaderHandler{db}
+	return handler
+}
+
+func (h *GetBlockHeaderHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
+	var requestJSON getBlockRequest
+	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
+	if err != nil {
+		writeInvalidRequest(ctx, "invalid request")
+		return
+	}
+	header, failure := findBlockHeader(h.db, requestJSON.BlockNumber, common.FromHex(requestJSON.Hash))
+	if failure != nil {
+		writeAPIError(ctx, failure)
+		return
+	}
+	writeGetBlockResponse(ctx, getBlockHeaderResponse{Error: false, blockHeaderDetails: newBlockHeaderDetails(header)})
+	return
+}
+
+// findBlockHeader selects a block by hash if it is given, by number otherwise
+func findBlockHeader(db storage.Database, blockNumber int, hash []byte) (*foundationdb.ArchivedBlockHeader, *apiError) {
+	number := uint32(blockNumber)
+	if len(hash) != 0 {
+		if len(hash) != foundationdb.BlockHashLength {
+			return nil, invalidRequest("invalid block hash")
+		}
+		var err error
+		number, err = foundationdb.LookupBlockNumberByHash(db, hash)
+		if err != nil {
+			return nil, notFound("block not found")
+		}
+	} else if blockNumber <= 0 {
+		return nil, invalidRequest("invalid block number")
+	}
+	header, err := foundationdb.GetArchivedBlockHeader(db, number)
+	if err != nil {
+		return nil, notFound("block not found")
+	}
+	return header, nil
+}
+
+func findBlock(db storage.Database, blockNumber int, hash []byte) (*blockDetails, *apiError) {
+	header, failure := findBlockHeader(db, blockNumber, hash)
+	if failure != nil {
+		return nil, failure
+	}
+	rawBlock, err := foundationdb.GetArchivedBlock(db, header.BlockNumber)
+	if err != nil {
+		return nil, notFound("block not found")
+	}
+	details := &blockDetails{BlockNumber: int(header.BlockNumber),
+		Hash: common.ToHex(header.Hash), Block: common.ToHex(rawBlock)}
+	return details, nil
+}
+
+func newBlockHeaderDetails(header *foundationdb.ArchivedBlockHeader) blockHeaderDetails {
+	return blockHeaderDetails{BlockNumber: int(header.BlockNumber),
+		NumberOfTransactions: int(header.NumberOfTransactions),
+		ParentHash:           common.ToHex(header.ParentHash),
+		MerkleTreeRoot:       common.ToHex(header.MerkleTreeRoot),
+		Hash:                 common.ToHex(header.Hash),
+		Header:               common.ToHex(header.RawHeader)}
+}
+
+func writeGetBlockResponse(ctx *fasthttp.RequestCtx, response interface{}) {
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
+}
@@ -1,15 +1,14 @@
 package handlers
 
 import (
-	"encoding/json"
-
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
 	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	"github.com/valyala/fasthttp"
 )
 
 type LastBlockHandler struct {
-	db *fdb.Database
+	db storage.Database
 }
 
 type lastBlockResponse struct {
@@ -17,7 +16,7 @@ type lastBlockResponse struct {
 	BlockNumber int  `json:"blockNumber"`
 }
 
-func NewLastBlockHandler(db *fdb.Database) *LastBlockHandler {
+func NewLastBlockHandler(db storage.Database) *LastBlockHandler {
 	handler := &LastBlockHandler{db}
 	return handler
 }
@@ -25,13 +24,10 @@ func NewLastBlockHandler(db *fdb.Database) *LastBlockHandler {
 func (h *LastBlockHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
 	lastBlock, err := foundationdb.GetLastWrittenBlock(h.db)
 	if err != nil {
-		writeGeneralErrorResponse(ctx)
+		writeStorageUnavailable(ctx, "failed to read last written block")
 		return
 	}
 	response := lastBlockResponse{Error: false, BlockNumber: int(lastBlock)}
-	body, _ := json.Marshal(response)
-	ctx.SetContentType("application/json")
-	ctx.SetBody(body)
-	ctx.SetStatusCode(fasthttp.StatusOK)
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
 	return
 }
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/rejections"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/valyala/fasthttp"
+)
+
+// getProofRequest selects a transaction by hash if it is given, by its place in a block otherwise
+type getProofRequest struct {
+	BlockNumber       int    `json:"blockNumber"`
+	TransactionNumber int    `json:"transactionNumber"`
+	Hash              string `json:"hash"`
+}
+
+type proofDetails struct {
+	BlockNumber       int      `json:"blockNumber,omitempty"`
+	TransactionNumber int      `json:"transactionNumber"`
+	TX                string   `json:"tx,omitempty"`
+	Proof             string   `json:"proof,omitempty"`
+	Branch            []string `json:"branch,omitempty"`
+	Root              string   `json:"root,omitempty"`
+}
+
+type getProofResponse struct {
+	Error bool `json:"error"`
+	proofDetails
+}
+
+// errProofMismatch is the tree of an archived block disagreeing with its signed root
+var errProofMismatch = apiError{"internal_error", "proof does not match the block header", fasthttp.StatusInternalServerError, false, 0}
+
+type GetProofHandler struct {
+	db       storage.Database
+	rejected rejections.Store
+}
+
+func NewGetProofHandler(db storage.Database, rejected rejections.Store) *GetProofHandler {
+	handler := &GetProofHandler{db, rejected}
+	return handler
+}
+
+func (h *GetProofHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
+	var requestJSON getProofRequest
+	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
+	if err != nil {
+		writeInvalidRequest(ctx, "invalid request")
+		return
+	}
+	details, failure := findProof(h.db, h.rejected, requestJSON.BlockNumber, requestJSON.TransactionNumber, requestJSON.Hash)
+	if failure != nil {
+		writeAPIError(ctx, failure)
+		return
+	}
+	writeGetProofResponse(ctx, getProofResponse{Error: false, proofDetails: *details})
+	return
+}
+
+// findProof selects a transaction by hash if it is given, by its place in a block otherwise
+func findProof(db storage.Database, rejected rejections.Store, blockNumber int, transactionNumber int, hash string) (*proofDetails, *apiError) {
+	number := uint32(blockNumber)
+	position := uint32(transactionNumber)
+	if hash != "" {
+		lookup, failure := lookupTransactionByHash(db, rejected, hash)
+		if failure != nil {
+			return nil, failure
+		}
+		if lookup.Rejected {
+			return nil, &apiError{"transaction_rejected", "transaction was rejected", fasthttp.StatusConflict, false, 0}
+		}
+		if lookup.Pending {
+			return nil, &apiError{"transaction_pending", "transaction is not included yet", fasthttp.StatusConflict, true, 0}
+		}
+		number = lookup.BlockNumber
+		position = lookup.TransactionNumber
+	} else if blockNumber <= 0 || transactionNumber < 0 {
+		return nil, invalidRequest("invalid transaction location")
+	}
+	proof, err := foundationdb.GetTransactionProof(db, number, position)
+	if err == foundationdb.ErrMerkleRootMismatch {
+		return nil, &errProofMismatch
+	}
+	if err != nil {
+		return nil, notFound("proof is not available")
+	}
+	// contracts take the branch as a single concatenated bytes argument
+	concatenated := []byte{}
+	branch := make([]string, len(proof.Branch))
+	for i, sibling := range proof.Branch {
+		concatenated = append(concatenated, sibling...)
+		branch[i] = common.ToHex(sibling)
+	}
+	details := &proofDetails{BlockNumber: int(proof.BlockNumber),
+		TransactionNumber: int(proof.TransactionNumber),
+		TX:                common.ToHex(proof.RawTransaction),
+		Proof:             common.ToHex(concatenated),
+		Branch:            branch,
+		Root:              common.ToHex(proof.Root)}
+	return details, nil
+}
+
+func writeGetProofResponse(ctx *fasthttp.RequestCtx, response getProofResponse) {
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
+}
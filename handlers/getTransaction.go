@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/rejections"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type getTransactionRequest struct {
+	Hash string `json:"hash"`
+}
+
+type transactionDetails struct {
+	Status            string `json:"status,omitempty"`
+	BlockNumber       int    `json:"blockNumber,omitempty"`
+	TransactionNumber *int   `json:"transactionNumber,omitempty"`
+	TX                string `json:"tx,omitempty"`
+	RejectionReason   string `json:"rejectionReason,omitempty"`
+}
+
+type getTransactionResponse struct {
+	Error bool `json:"error"`
+	transactionDetails
+}
+
+type GetTransactionHandler struct {
+	db       storage.Database
+	rejected rejections.Store
+}
+
+func NewGetTransactionHandler(db storage.Database, rejected rejections.Store) *GetTransactionHandler {
+	handler := &GetTransactionHandler{db, rejected}
+	return handler
+}
+
+func (h *GetTransactionHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
+	var requestJSON getTransactionRequest
+	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
+	if err != nil {
+		writeInvalidRequest(ctx, "invalid request")
+		return
+	}
+	result, failure := lookupTransactionByHash(h.db, h.rejected, requestJSON.Hash)
+	if failure != nil {
+		writeAPIError(ctx, failure)
+		return
+	}
+	response := getTransactionResponse{Error: false, transactionDetails: newTransactionDetails(result, true)}
+	writeGetTransactionResponse(ctx, response)
+	return
+}
+
+func lookupTransactionByHash(db storage.Database, rejected rejections.Store, hashString string) (*foundationdb.TransactionLookupResult, *apiError) {
+	hash := common.FromHex(hashString)
+	if len(hash) != foundationdb.TransactionHashLength {
+		return nil, invalidRequest("invalid transaction hash")
+	}
+	result, err := foundationdb.LookupTransactionOrRejection(db, rejected, hash)
+	if err != nil {
+		return nil, notFound("transaction not found")
+	}
+	return result, nil
+}
+
+func newTransactionDetails(result *foundationdb.TransactionLookupResult, withTransaction bool) transactionDetails {
+	if result.Rejected {
+		return transactionDetails{Status: "rejected", RejectionReason: result.RejectionReason}
+	}
+	details := transactionDetails{BlockNumber: int(result.BlockNumber)}
+	if withTransaction {
+		details.TX = common.ToHex(result.RawTransaction)
+	}
+	if result.Pending {
+		// position inside a block is only known after assembly
+		details.Status = "pending"
+	} else {
+		transactionNumber := int(result.TransactionNumber)
+		details.Status = "included"
+		details.TransactionNumber = &transactionNumber
+	}
+	return details
+}
+
+func writeGetTransactionResponse(ctx *fasthttp.RequestCtx, response getTransactionResponse) {
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/rejections"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/valyala/fasthttp"
+)
+
+// getTransactionStatusRequest selects a transaction by hash if it is given, by the counter
+// returned from /sendRawTX otherwise
+type getTransactionStatusRequest struct {
+	Hash    string `json:"hash"`
+	Counter uint64 `json:"counter"`
+}
+
+type getTransactionStatusResponse struct {
+	Error bool `json:"error"`
+	transactionDetails
+}
+
+type GetTransactionStatusHandler struct {
+	db       storage.Database
+	rejected rejections.Store
+}
+
+func NewGetTransactionStatusHandler(db storage.Database, rejected rejections.Store) *GetTransactionStatusHandler {
+	handler := &GetTransactionStatusHandler{db, rejected}
+	return handler
+}
+
+func (h *GetTransactionStatusHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
+	var requestJSON getTransactionStatusRequest
+	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
+	if err != nil {
+		writeInvalidRequest(ctx, "invalid request")
+		return
+	}
+	details, failure := transactionStatus(h.db, h.rejected, requestJSON.Hash, requestJSON.Counter)
+	if failure != nil {
+		writeAPIError(ctx, failure)
+		return
+	}
+	writeGetTransactionStatusResponse(ctx, getTransactionStatusResponse{Error: false, transactionDetails: *details})
+	return
+}
+
+func transactionStatus(db storage.Database, rejected rejections.Store, hash string, counter uint64) (*transactionDetails, *apiError) {
+	var result *foundationdb.TransactionLookupResult
+	if hash != "" {
+		var failure *apiError
+		result, failure = lookupTransactionByHash(db, rejected, hash)
+		if failure != nil {
+			return nil, failure
+		}
+	} else if counter != 0 {
+		var err error
+		result, err = foundationdb.LookupTransactionByCounter(db, counter)
+		if err != nil {
+			return nil, notFound("transaction not found")
+		}
+	} else {
+		return nil, invalidRequest("hash or counter is required")
+	}
+	details := newTransactionDetails(result, false)
+	return &details, nil
+}
+
+func writeGetTransactionStatusResponse(ctx *fasthttp.RequestCtx, response getTransactionStatusResponse) {
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/plasmapb"
+	"github.com/matterinc/PlasmaBlockCreator/ratelimit"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/valyala/fasthttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// MaxStreamedTransactions limits one SendRawTransactions stream, results are kept
+// until the client closes it
+const MaxStreamedTransactions = 100000
+
+// grpcUTXOStates maps to the states of the HTTP API
+var grpcUTXOStates = map[plasmapb.UTXOState]string{
+	plasmapb.UTXOState_SPENDABLE:    utxoStateSpendable,
+	plasmapb.UTXOState_EXIT_PENDING: utxoStateExitPending,
+	plasmapb.UTXOState_ALL:          utxoStateAll,
+}
+
+// GRPCServer serves the same operations as the REST routes over gRPC,
+// every binary registers only the methods it has services for
+type GRPCServer struct {
+	plasmapb.UnimplementedPlasmaServer
+	sendRawTX  *SendRawTXHandler
+	utxoLister *ListUTXOsHandler
+	db         storage.Database
+}
+
+func NewGRPCServer() *GRPCServer {
+	server := &GRPCServer{}
+	return server
+}
+
+func (s *GRPCServer) RegisterSendMethods(sendRawTX *SendRawTXHandler) {
+	s.sendRawTX = sendRawTX
+}
+
+func (s *GRPCServer) RegisterReadMethods(db storage.Database) {
+	s.db = db
+	s.utxoLister = NewListUTXOsHandler(db)
+}
+
+func (s *GRPCServer) SendRawTransaction(ctx context.Context, tx *plasmapb.RawTransaction) (*plasmapb.SendResult, error) {
+	if s.sendRawTX == nil {
+		return nil, status.Error(codes.Unimplemented, "transactions are not accepted by this server")
+	}
+	result, failure := s.send(grpcClient(ctx), tx.GetTx())
+	if failure != nil {
+		return nil, newGRPCError(failure)
+	}
+	return result, nil
+}
+
+// SendRawTransactions reports failures per transaction instead of breaking the stream
+func (s *GRPCServer) SendRawTransactions(stream plasmapb.Plasma_SendRawTransactionsServer) error {
+	if s.sendRawTX == nil {
+		return status.Error(codes.Unimplemented, "transactions are not accepted by this server")
+	}
+	summary := &plasmapb.SendSummary{}
+	client := grpcClient(stream.Context())
+	for {
+		tx, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(summary)
+		}
+		if err != nil {
+			return err
+		}
+		if len(summary.Results) >= MaxStreamedTransactions {
+			return status.Error(codes.ResourceExhausted, "stream is longer than the limit of transactions")
+		}
+		result, _ := s.send(client, tx.GetTx())
+		if result.Accepted {
+			summary.Accepted++
+		}
+		summary.Results = append(summary.Results, result)
+	}
+}
+
+func (s *GRPCServer) send(client string, raw []byte) (*plasmapb.SendResult, *apiError) {
+	if len(raw) == 0 {
+		return &plasmapb.SendResult{Error: newGRPCErrorDetails(&errInvalidEncoding)}, &errInvalidEncoding
+	}
+	hash, counter, failure := s.sendRawTX.submit(client, raw)
+	if failure != nil {
+		return &plasmapb.SendResult{Hash: hash, Error: newGRPCErrorDetails(failure)}, failure
+	}
+	return &plasmapb.SendResult{Accepted: true, Hash: hash, Counter: counter}, nil
+}
+
+func (s *GRPCServer) ListUTXOs(ctx context.Context, request *plasmapb.ListUTXOsRequest) (*plasmapb.ListUTXOsResponse, error) {
+	if s.db == nil {
+		return nil, status.Error(codes.Unimplemented, "reads are not served by this server")
+	}
+	if len(request.GetAddress()) != common.AddressLength {
+		return nil, newGRPCError(invalidRequest("invalid address"))
+	}
+	state, ok := grpcUTXOStates[request.GetState()]
+	if !ok {
+		return nil, newGRPCError(invalidRequest("unknown state"))
+	}
+	address := common.BytesToAddress(request.GetAddress())
+	page, failure := s.utxoLister.list(address, listUTXOsRequest{BlockNumber: int(request.GetBlockNumber()),
+		TransactionNumber: int(request.GetTransactionNumber()),
+		OutputNumber:      int(request.GetOutputNumber()),
+		Limit:             int(request.GetLimit()),
+		Cursor:            request.GetCursor(),
+		State:             state})
+	if failure != nil {
+		return nil, newGRPCError(failure)
+	}
+	response := &plasmapb.ListUTXOsResponse{Utxos: make([]*plasmapb.UTXO, 0, len(page.UTXOs)),
+		HasMore: page.HasMore,
+		Cursor:  page.Cursor}
+	for _, utxo := range page.UTXOs {
+		response.Utxos = append(response.Utxos, &plasmapb.UTXO{BlockNumber: uint32(utxo.BlockNumber),
+			TransactionNumber: uint32(utxo.TransactionNumber),
+			OutputNumber:      uint32(utxo.OutputNumber),
+			Value:             utxo.Value,
+			ExitPending:       utxo.State == utxoStateExitPending})
+	}
+	return response, nil
+}
+
+func (s *GRPCServer) GetLastWrittenBlock(ctx context.Context, request *plasmapb.LastWrittenBlockRequest) (*plasmapb.LastWrittenBlock, error) {
+	if s.db == nil {
+		return nil, status.Error(codes.Unimplemented, "reads are not served by this server")
+	}
+	lastBlock, err := foundationdb.GetLastWrittenBlock(s.db)
+	if err != nil {
+		return nil, newGRPCError(storageUnavailable("failed to read last written block"))
+	}
+	return &plasmapb.LastWrittenBlock{BlockNumber: lastBlock}, nil
+}
+
+func (s *GRPCServer) GetBlockHeader(ctx context.Context, request *plasmapb.BlockRequest) (*plasmapb.BlockHeader, error) {
+	if s.db == nil {
+		return nil, status.Error(codes.Unimplemented, "reads are not served by this server")
+	}
+	header, failure := findBlockHeader(s.db, int(request.GetBlockNumber()), request.GetHash())
+	if failure != nil {
+		return nil, newGRPCError(failure)
+	}
+	return &plasmapb.BlockHeader{BlockNumber: header.BlockNumber,
+		NumberOfTransactions: header.NumberOfTransactions,
+		ParentHash:           header.ParentHash,
+		MerkleTreeRoot:       header.MerkleTreeRoot,
+		Hash:                 header.Hash,
+		RawHeader:            header.RawHeader}, nil
+}
+
+func (s *GRPCServer) GetBlock(ctx context.Context, request *plasmapb.BlockRequest) (*plasmapb.Block, error) {
+	if s.db == nil {
+		return nil, status.Error(codes.Unimplemented, "reads are not served by this server")
+	}
+	header, failure := findBlockHeader(s.db, int(request.GetBlockNumber()), request.GetHash())
+	if failure != nil {
+		return nil, newGRPCError(failure)
+	}
+	rawBlock, err := foundationdb.GetArchivedBlock(s.db, header.BlockNumber)
+	if err != nil {
+		return nil, newGRPCError(notFound("block not found"))
+	}
+	return &plasmapb.Block{BlockNumber: header.BlockNumber, Hash: header.Hash, RawBlock: rawBlock}, nil
+}
+
+// GRPCLimit returns interceptors holding a slot of the concurrency limit shared with the HTTP
+// server for every call, grpc.MaxConcurrentStreams only caps a single connection
+func GRPCLimit(concurrency *ratelimit.Concurrency) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !concurrency.Acquire() {
+			return nil, status.Error(codes.Unavailable, "too many concurrent requests")
+		}
+		defer concurrency.Release()
+		return handler(ctx, request)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !concurrency.Acquire() {
+			return status.Error(codes.Unavailable, "too many concurrent requests")
+		}
+		defer concurrency.Release()
+		return handler(srv, ss)
+	}
+	return unary, stream
+}
+
+func newGRPCErrorDetails(failure *apiError) *plasmapb.Error {
+	return &plasmapb.Error{Code: failure.Code, Message: failure.Message, Retryable: failure.Retryable,
+		RetryAfter: uint32(failure.retryAfterSeconds())}
+}
+
+// grpcClient returns the IP of the peer for rate limits, the whole address if it has no port
+func grpcClient(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// newGRPCError maps the HTTP status of a failure to a gRPC code, the REST error code
+// is kept in front of the message
+func newGRPCError(failure *apiError) error {
+	code := codes.Internal
+	switch failure.StatusCode {
+	case fasthttp.StatusBadRequest:
+		code = codes.InvalidArgument
+	case fasthttp.StatusNotFound:
+		code = codes.NotFound
+	case fasthttp.StatusConflict:
+		code = codes.FailedPrecondition
+		if failure.Retryable {
+			code = codes.Aborted
+		}
+	case fasthttp.StatusUnprocessableEntity:
+		code = codes.FailedPrecondition
+	case fasthttp.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case fasthttp.StatusServiceUnavailable:
+		code = codes.Unavailable
+	}
+	return status.Error(code, failure.Code+": "+failure.Message)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matterinc/PlasmaBlockCreator/plasmapb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCReadMethods(t *testing.T) {
+	s := NewGRPCServer()
+	_, err := s.SendRawTransaction(context.Background(), &plasmapb.RawTransaction{Tx: []byte{0x01}})
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatal("Unregistered method should be unimplemented")
+	}
+	s.RegisterReadMethods(storage.NewMemoryDatabase())
+	_, err = s.ListUTXOs(context.Background(), &plasmapb.ListUTXOsRequest{Address: []byte{0x01}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatal("Short address should be an invalid argument")
+	}
+	response, err := s.ListUTXOs(context.Background(), &plasmapb.ListUTXOsRequest{Address: make([]byte, 20)})
+	if err != nil || len(response.Utxos) != 0 {
+		t.Fatal("Empty address should have no outputs")
+	}
+	_, err = s.GetBlockHeader(context.Background(), &plasmapb.BlockRequest{Selector: &plasmapb.BlockRequest_BlockNumber{BlockNumber: 1}})
+	if status.Code(err) != codes.NotFound {
+		t.Fatal("Missing block should not be found")
+	}
+	_, err = s.GetBlock(context.Background(), &plasmapb.BlockRequest{Selector: &plasmapb.BlockRequest_Hash{Hash: []byte{0x01}}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatal("Short hash should be an invalid argument")
+	}
+}
+
+func TestGRPCErrorCodes(t *testing.T) {
+	if status.Code(newGRPCError(&errCounterConflict)) != codes.Aborted {
+		t.Fatal("Retryable conflict should be aborted")
+	}
+	if status.Code(newGRPCError(&errDoubleSpend)) != codes.FailedPrecondition {
+		t.Fatal("Double spend should be a failed precondition")
+	}
+	if status.Code(newGRPCError(&errStorageUnavailable)) != codes.Unavailable {
+		t.Fatal("Storage failure should be unavailable")
+	}
+}
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/rejections"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// MaxJSONRPCBatchSize limits the number of calls in one batch
+	MaxJSONRPCBatchSize = 100
+	// MaxJSONRPCBodySize fits a full batch of the largest calls
+	MaxJSONRPCBodySize = MaxJSONRPCBatchSize * MaxJSONBodySize
+)
+
+// error codes from the JSON-RPC 2.0 specification, failures of the operator itself
+// use jsonRPCServerError and carry the same code as the REST API in data
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+	jsonRPCServerError    = -32000
+)
+
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type jsonRPCErrorData struct {
+	Code      string `json:"code"`
+	Retryable bool   `json:"retryable,omitempty"`
+	// RetryAfter is in seconds, only for rate_limited
+	RetryAfter int `json:"retryAfter,omitempty"`
+}
+
+type jsonRPCError struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Data    *jsonRPCErrorData `json:"data,omitempty"`
+}
+
+// jsonRPCResponse has an encoded result, so a successful call has the member even if the
+// result is null, and a failed one has none
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type sendRawTransactionResult struct {
+	Hash    string `json:"hash"`
+	Counter uint64 `json:"counter,omitempty"`
+}
+
+// jsonRPCMethod gets the IP of the client for rate limits
+type jsonRPCMethod func(client string, params json.RawMessage) (interface{}, *jsonRPCError)
+
+// JSONRPCHandler serves the same operations as the REST routes as JSON-RPC 2.0 methods,
+// every binary registers only the methods it has services for
+type JSONRPCHandler struct {
+	methods map[string]jsonRPCMethod
+}
+
+func NewJSONRPCHandler() *JSONRPCHandler {
+	handler := &JSONRPCHandler{make(map[string]jsonRPCMethod)}
+	return handler
+}
+
+func (h *JSONRPCHandler) RegisterSendMethods(sendRawTX *SendRawTXHandler) {
+	h.methods["plasma_sendRawTransaction"] = func(client string, params json.RawMessage) (interface{}, *jsonRPCError) {
+		var tx string
+		failure := positionalParams(params, 1, &tx)
+		if failure != nil {
+			return nil, failure
+		}
+		raw := common.FromHex(tx)
+		if len(raw) == 0 {
+			return nil, newJSONRPCError(&errInvalidEncoding)
+		}
+		hash, counter, rejection := sendRawTX.submit(client, raw)
+		if rejection != nil {
+			return nil, newJSONRPCError(rejection)
+		}
+		return sendRawTransactionResult{common.ToHex(hash), counter}, nil
+	}
+}
+
+func (h *JSONRPCHandler) RegisterReadMethods(db storage.Database, rejected rejections.Store) {
+	utxoLister := NewListUTXOsHandler(db)
+	balanceReader := NewGetBalanceHandler(db)
+	h.methods["plasma_listUTXOs"] = func(client string, params json.RawMessage) (interface{}, *jsonRPCError) {
+		var request listUTXOsRequest
+		failure := positionalParams(params, 1, &request.For, &request.BlockNumber,
+			&request.TransactionNumber, &request.OutputNumber, &request.Limit, &request.Cursor, &request.State)
+		if failure != nil {
+			return nil, failure
+		}
+		forBytes := common.FromHex(request.For)
+		address := common.Address{}
+		copy(address[:], forBytes)
+		return jsonRPCResult(utxoLister.list(address, request))
+	}
+	h.methods["plasma_lastWrittenBlock"] = func(client string, params json.RawMessage) (interface{}, *jsonRPCError) {
+		failure := positionalParams(params, 0)
+		if failure != nil {
+			return nil, failure
+		}
+		lastBlock, err := foundationdb.GetLastWrittenBlock(db)
+		if err != nil {
+			return nil, newJSONRPCError(storageUnavailable("failed to read last written block"))
+		}
+		return int(lastBlock), nil
+	}
+	h.methods["plasma_getBalance"] = func(client string, params json.RawMessage) (interface{}, *jsonRPCError) {
+		var address string
+		failure := positionalParams(params, 1, &address)
+		if failure != nil {
+			return nil, failure
+		}
+		return jsonRPCResult(balanceReader.balance(address))
+	}
+	h.methods["plasma_getTransaction"] = func(client string, params json.RawMessage) (interface{}, *jsonRPCError) {
+		var hash string
+		failure := positionalParams(params, 1, &hash)
+		if failure != nil {
+			return nil, failure
+		}
+		result, lookupFailure := lookupTransactionByHash(db, rejected, hash)
+		if lookupFailure != nil {
+			return nil, newJSONRPCError(lookupFailure)
+		}
+		return newTransactionDetails(result, true), nil
+	}
+	h.methods["plasma_getTransactionStatus"] = func(client string, params json.RawMessage) (interface{}, *jsonRPCError) {
+		counter, hash, failure := numberOrHashParam(params)
+		if failure != nil {
+			return nil, failure
+		}
+		return jsonRPCResult(transactionStatus(db, rejected, hash, counter))
+	}
+	h.methods["plasma_getBlock"] = func(client string, params json.RawMessage) (interface{}, *jsonRPCError) {
+		blockNumber, hash, failure := numberOrHashParam(params)
+		if failure != nil {
+			return nil, failure
+		}
+		return jsonRPCResult(findBlock(db, int(blockNumber), common.FromHex(hash)))
+	}
+	h.methods["plasma_getBlockHeader"] = func(client string, params json.RawMessage) (interface{}, *jsonRPCError) {
+		blockNumber, hash, failure := numberOrHashParam(params)
+		if failure != nil {
+			return nil, failure
+		}
+		header, lookupFailure := findBlockHeader(db, int(blockNumber), common.FromHex(hash))
+		if lookupFailure != nil {
+			return nil, newJSONRPCError(lookupFailure)
+		}
+		return newBlockHeaderDetails(header), nil
+	}
+	// takes either a transaction hash or a block number and a transaction number
+	h.methods["plasma_getProof"] = func(client string, params json.RawMessage) (interface{}, *jsonRPCError) {
+		var values []json.RawMessage
+		if len(params) != 0 {
+			err := json.Unmarshal(params, &values)
+			if err != nil {
+				return nil, &jsonRPCError{Code: jsonRPCInvalidParams, Message: "params should be an array"}
+			}
+		}
+		if len(values) == 1 {
+			var hash string
+			failure := positionalParams(params, 1, &hash)
+			if failure != nil {
+				return nil, failure
+			}
+			return jsonRPCResult(findProof(db, rejected, 0, 0, hash))
+		}
+		var blockNumber, transactionNumber int
+		failure := positionalParams(params, 2, &blockNumber, &transactionNumber)
+		if failure != nil {
+			return nil, failure
+		}
+		return jsonRPCResult(findProof(db, rejected, blockNumber, transactionNumber, ""))
+	}
+}
+
+func (h *JSONRPCHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
+	body := bytes.TrimSpace(ctx.PostBody())
+	if !json.Valid(body) {
+		writeJSONRPCResponse(ctx, newJSONRPCFailure(nil, jsonRPCParseError, "parse error"))
+		return
+	}
+	if body[0] != '[' {
+		response := h.call(router.ClientIP(ctx), body)
+		if response == nil {
+			ctx.SetStatusCode(fasthttp.StatusNoContent)
+			return
+		}
+		writeJSONRPCResponse(ctx, response)
+		return
+	}
+	var batch []json.RawMessage
+	err := json.Unmarshal(body, &batch)
+	if err != nil {
+		writeJSONRPCResponse(ctx, newJSONRPCFailure(nil, jsonRPCInvalidRequest, "invalid batch"))
+		return
+	}
+	if len(batch) == 0 {
+		writeJSONRPCResponse(ctx, newJSONRPCFailure(nil, jsonRPCInvalidRequest, "empty batch"))
+		return
+	}
+	if len(batch) > MaxJSONRPCBatchSize {
+		writeJSONRPCResponse(ctx, newJSONRPCFailure(nil, jsonRPCInvalidRequest, "batch is larger than "+strconv.Itoa(MaxJSONRPCBatchSize)+" calls"))
+		return
+	}
+	responses := []*jsonRPCResponse{}
+	for _, raw := range batch {
+		response := h.call(router.ClientIP(ctx), raw)
+		if response != nil {
+			responses = append(responses, response)
+		}
+	}
+	// a batch of notifications only is answered with nothing at all
+	if len(responses) == 0 {
+		ctx.SetStatusCode(fasthttp.StatusNoContent)
+		return
+	}
+	writeJSONRPCResponse(ctx, responses)
+	return
+}
+
+// call runs a single request and returns nil for notifications
+func (h *JSONRPCHandler) call(client string, raw json.RawMessage) *jsonRPCResponse {
+	var request jsonRPCRequest
+	err := json.Unmarshal(raw, &request)
+	if err != nil || request.JSONRPC != "2.0" || request.Method == "" {
+		return newJSONRPCFailure(request.ID, jsonRPCInvalidRequest, "invalid request")
+	}
+	method, ok := h.methods[request.Method]
+	if !ok {
+		if request.ID == nil {
+			return nil
+		}
+		return newJSONRPCFailure(request.ID, jsonRPCMethodNotFound, "method "+request.Method+" not found")
+	}
+	result, failure := method(client, request.Params)
+	if request.ID == nil {
+		return nil
+	}
+	if failure != nil {
+		return &jsonRPCResponse{JSONRPC: "2.0", Error: failure, ID: request.ID}
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return newJSONRPCFailure(request.ID, jsonRPCServerError, "failed to encode result")
+	}
+	return &jsonRPCResponse{JSONRPC: "2.0", Result: encoded, ID: request.ID}
+}
+
+// positionalParams reads params given as an array, trailing optional ones may be omitted
+func positionalParams(params json.RawMessage, required int, args ...interface{}) *jsonRPCError {
+	var values []json.RawMessage
+	if len(params) != 0 && string(params) != "null" {
+		err := json.Unmarshal(params, &values)
+		if err != nil {
+			return &jsonRPCError{Code: jsonRPCInvalidParams, Message: "params should be an array"}
+		}
+	}
+	if len(values) < required || len(values) > len(args) {
+		return &jsonRPCError{Code: jsonRPCInvalidParams, Message: "wrong number of params"}
+	}
+	for i, value := range values {
+		err := json.Unmarshal(value, args[i])
+		if err != nil {
+			return &jsonRPCError{Code: jsonRPCInvalidParams, Message: "invalid param " + strconv.Itoa(i)}
+		}
+	}
+	return nil
+}
+
+// numberOrHashParam reads a single param that is a hex string hash or a number
+func numberOrHashParam(params json.RawMessage) (uint64, string, *jsonRPCError) {
+	var value json.RawMessage
+	failure := positionalParams(params, 1, &value)
+	if failure != nil {
+		return 0, "", failure
+	}
+	var hash string
+	if json.Unmarshal(value, &hash) == nil {
+		return 0, hash, nil
+	}
+	var number uint64
+	if json.Unmarshal(value, &number) == nil {
+		return number, "", nil
+	}
+	return 0, "", &jsonRPCError{Code: jsonRPCInvalidParams, Message: "param should be a hash or a number"}
+}
+
+func jsonRPCResult(result interface{}, failure *apiError) (interface{}, *jsonRPCError) {
+	if failure != nil {
+		return nil, newJSONRPCError(failure)
+	}
+	return result, nil
+}
+
+// newJSONRPCError keeps the REST error code in data, malformed input is reported as invalid params
+func newJSONRPCError(failure *apiError) *jsonRPCError {
+	code := jsonRPCServerError
+	if failure.StatusCode == fasthttp.StatusBadRequest {
+		code = jsonRPCInvalidParams
+	}
+	return &jsonRPCError{code, failure.Message, &jsonRPCErrorData{failure.Code, failure.Retryable, failure.retryAfterSeconds()}}
+}
+
+func newJSONRPCFailure(id json.RawMessage, code int, message string) *jsonRPCResponse {
+	return &jsonRPCResponse{JSONRPC: "2.0", Error: &jsonRPCError{Code: code, Message: message}, ID: id}
+}
+
+func writeJSONRPCResponse(ctx *fasthttp.RequestCtx, response interface{}) {
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
+}
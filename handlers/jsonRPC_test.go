@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/matterinc/PlasmaBlockCreator/rejections"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/valyala/fasthttp"
+)
+
+func callJSONRPC(h *JSONRPCHandler, body string) *fasthttp.RequestCtx {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod("POST")
+	ctx.Request.SetBodyString(body)
+	h.HandlerFunc(ctx)
+	return ctx
+}
+
+func TestJSONRPCBatch(t *testing.T) {
+	h := NewJSONRPCHandler()
+	h.RegisterReadMethods(storage.NewMemoryDatabase(), rejections.NewMemoryStore(time.Hour, 10))
+	ctx := callJSONRPC(h, `[
+		{"jsonrpc": "2.0", "method": "plasma_getBalance", "params": ["0xb3318181a88e26aC76b2ea385004FE367725e440"], "id": 1},
+		{"jsonrpc": "2.0", "method": "plasma_getBalance", "params": ["0x01"], "id": "two"},
+		{"jsonrpc": "2.0", "method": "plasma_unknown", "id": 3},
+		{"jsonrpc": "2.0", "method": "plasma_getBalance", "params": ["0xb3318181a88e26aC76b2ea385004FE367725e440"]},
+		{"method": "plasma_getBalance", "id": 4}
+	]`)
+	var responses []struct {
+		Result *balanceDetails `json:"result"`
+		Error  *jsonRPCError   `json:"error"`
+		ID     json.RawMessage `json:"id"`
+	}
+	err := json.Unmarshal(ctx.Response.Body(), &responses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 4 {
+		t.Fatal("Notification should not be answered")
+	}
+	if responses[0].Error != nil || responses[0].Result == nil || responses[0].Result.Total != "0" || string(responses[0].ID) != "1" {
+		t.Fatal("Balance call failed")
+	}
+	if responses[1].Error == nil || responses[1].Error.Code != jsonRPCInvalidParams || responses[1].Error.Data.Code != "invalid_request" || string(responses[1].ID) != `"two"` {
+		t.Fatal("Invalid address should be reported as invalid params")
+	}
+	if responses[2].Error == nil || responses[2].Error.Code != jsonRPCMethodNotFound {
+		t.Fatal("Unknown method should not be found")
+	}
+	if responses[3].Error == nil || responses[3].Error.Code != jsonRPCInvalidRequest {
+		t.Fatal("Request without version should be invalid")
+	}
+}
+
+func TestJSONRPCMalformedRequests(t *testing.T) {
+	h := NewJSONRPCHandler()
+	ctx := callJSONRPC(h, `{"jsonrpc": "2.0", "method"`)
+	var response jsonRPCResponse
+	json.Unmarshal(ctx.Response.Body(), &response)
+	if response.Error == nil || response.Error.Code != jsonRPCParseError || string(response.ID) != "null" {
+		t.Fatal("Broken JSON should be a parse error")
+	}
+	ctx = callJSONRPC(h, `[]`)
+	response = jsonRPCResponse{}
+	json.Unmarshal(ctx.Response.Body(), &response)
+	if response.Error == nil || response.Error.Code != jsonRPCInvalidRequest {
+		t.Fatal("Empty batch should be invalid")
+	}
+	ctx = callJSONRPC(h, `{"jsonrpc": "2.0", "method": "plasma_unknown"}`)
+	if ctx.Response.StatusCode() != fasthttp.StatusNoContent || len(ctx.Response.Body()) != 0 {
+		t.Fatal("Notification should be answered with nothing")
+	}
+}
+
+func TestJSONRPCAlwaysHasResultOnSuccess(t *testing.T) {
+	h := NewJSONRPCHandler()
+	h.RegisterReadMethods(storage.NewMemoryDatabase(), rejections.NewMemoryStore(time.Hour, 10))
+	h.methods["test_nothing"] = func(client string, params json.RawMessage) (interface{}, *jsonRPCError) {
+		return nil, nil
+	}
+	ctx := callJSONRPC(h, `{"jsonrpc": "2.0", "method": "test_nothing", "id": 1}`)
+	var response map[string]json.RawMessage
+	json.Unmarshal(ctx.Response.Body(), &response)
+	result, ok := response["result"]
+	if !ok || string(result) != "null" {
+		t.Fatal("Successful call should have a null result")
+	}
+	if _, ok := response["error"]; ok {
+		t.Fatal("Successful call should have no error")
+	}
+	ctx = callJSONRPC(h, `{"jsonrpc": "2.0", "method": "plasma_getProof", "params": {"hash": "0x01"}, "id": 2}`)
+	response = map[string]json.RawMessage{}
+	json.Unmarshal(ctx.Response.Body(), &response)
+	if _, ok := response["result"]; ok {
+		t.Fatal("Failed call should have no result")
+	}
+	var failure jsonRPCError
+	json.Unmarshal(response["error"], &failure)
+	if failure.Code != jsonRPCInvalidParams {
+		t.Fatal("Params that are not an array should be invalid")
+	}
+}
@@ -1,23 +1,31 @@
 package handlers
 
 import (
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
+	"math"
 	"net/http"
 
+	"github.com/matterinc/PlasmaBlockCreator/router"
 	"github.com/matterinc/PlasmaCommons/transaction"
 	"github.com/valyala/fasthttp"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
 	common "github.com/ethereum/go-ethereum/common"
 	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 )
 
+// listUTXOsRequest starts from the cursor of a previous page if it is given, from the
+// given output otherwise
 type listUTXOsRequest struct {
 	For               string `json:"for"`
 	BlockNumber       int    `json:"blockNumber"`
 	TransactionNumber int    `json:"transactionNumber"`
 	OutputNumber      int    `json:"outputNumber"`
 	Limit             int    `json:"limit,omitempty"`
+	Cursor            string `json:"cursor,omitempty"`
+	State             string `json:"state,omitempty"`
 }
 
 type singleUTXOdetails struct {
@@ -25,19 +33,44 @@ type singleUTXOdetails struct {
 	TransactionNumber int    `json:"transactionNumber"`
 	OutputNumber      int    `json:"outputNumber"`
 	Value             string `json:"value"`
+	State             string `json:"state"`
+}
+
+// listUTXOsPage has a cursor after its last output, hasMore tells whether to ask for the next page
+type listUTXOsPage struct {
+	UTXOs   []singleUTXOdetails `json:"utxos"`
+	HasMore bool                `json:"hasMore"`
+	Cursor  string              `json:"cursor,omitempty"`
 }
 
 type listUTXOsResponse struct {
-	Error bool                `json:"error"`
-	UTXOs []singleUTXOdetails `json:"utxos"`
+	Error bool `json:"error"`
+	listUTXOsPage
 }
 
+// states of outputs in requests and responses
+const (
+	utxoStateSpendable   = "spendable"
+	utxoStateExitPending = "exitPending"
+	utxoStateAll         = "all"
+)
+
+var utxoStates = map[string]foundationdb.UTXOState{
+	"":                   foundationdb.UTXOStateSpendable,
+	utxoStateSpendable:   foundationdb.UTXOStateSpendable,
+	utxoStateExitPending: foundationdb.UTXOStateExitPending,
+	utxoStateAll:         foundationdb.UTXOStateAny,
+}
+
+// utxoCursorVersion is the first byte of a cursor, so its layout can change later
+const utxoCursorVersion = 0x01
+
 type ListUTXOsHandler struct {
-	db         *fdb.Database
+	db         storage.Database
 	utxoLister *foundationdb.UTXOlister
 }
 
-func NewListUTXOsHandler(db *fdb.Database) *ListUTXOsHandler {
+func NewListUTXOsHandler(db storage.Database) *ListUTXOsHandler {
 	lister := foundationdb.NewUTXOlister(db)
 	handler := &ListUTXOsHandler{db, lister}
 	return handler
@@ -47,70 +80,100 @@ func (h *ListUTXOsHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
 	var requestJSON listUTXOsRequest
 	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
 	if err != nil {
-		writeEmptyFasthttpResponse(ctx)
+		writeInvalidRequest(ctx, "invalid request")
 		return
 	}
-
 	forBytes := common.FromHex(requestJSON.For)
 	address := common.Address{}
 	copy(address[:], forBytes)
-	blockNumber := uint32(requestJSON.BlockNumber)
-	transactionNumber := uint32(requestJSON.TransactionNumber)
-	outputNumber := uint8(requestJSON.OutputNumber)
+	page, failure := h.list(address, requestJSON)
+	if failure != nil {
+		writeAPIError(ctx, failure)
+		return
+	}
+	writeFasthttpResponse(ctx, page)
+	return
+}
+
+// list returns a page of outputs of an address, at most 100 of them
+func (h *ListUTXOsHandler) list(address common.Address, request listUTXOsRequest) (*listUTXOsPage, *apiError) {
 	limit := 50
-	if requestJSON.Limit != 0 {
-		limit = requestJSON.Limit
+	if request.Limit > 0 {
+		limit = request.Limit
 	}
 	if limit > 100 {
 		limit = 100
 	}
-	// limit := 0
-	utxos, err := h.utxoLister.GetUTXOsForAddress(address, blockNumber, transactionNumber, outputNumber, limit, false)
+	state, ok := utxoStates[request.State]
+	if !ok {
+		return nil, invalidRequest("state should be spendable, exitPending or all")
+	}
+	from := [transaction.UTXOIndexLength]byte{}
+	exclusive := request.Cursor != ""
+	if exclusive {
+		from, ok = decodeUTXOCursor(request.Cursor)
+		if !ok {
+			return nil, invalidRequest("invalid cursor")
+		}
+	} else {
+		if request.BlockNumber < 0 || int64(request.BlockNumber) > math.MaxUint32 ||
+			request.TransactionNumber < 0 || int64(request.TransactionNumber) > math.MaxUint32 ||
+			request.OutputNumber < 0 || request.OutputNumber > math.MaxUint8 {
+			return nil, invalidRequest("invalid output number")
+		}
+		position := from[transaction.AddressLength:]
+		binary.BigEndian.PutUint32(position, uint32(request.BlockNumber))
+		binary.BigEndian.PutUint32(position[transaction.BlockNumberLength:], uint32(request.TransactionNumber))
+		position[transaction.BlockNumberLength+transaction.TransactionNumberLength] = uint8(request.OutputNumber)
+	}
+	utxos, hasMore, err := h.utxoLister.ListUTXOsForAddress(address, from, exclusive, state, limit)
 	if err != nil {
-		writeEmptyFasthttpResponse(ctx)
-		return
+		return nil, storageUnavailable("failed to list UTXOs")
 	}
-	details := make([]singleUTXOdetails, len(utxos))
+	page := &listUTXOsPage{UTXOs: make([]singleUTXOdetails, len(utxos)), HasMore: hasMore}
 	for i, utxo := range utxos {
-		detail := transaction.ParseIndexIntoUTXOdetails(utxo)
+		detail := transaction.ParseIndexIntoUTXOdetails(utxo.Index)
 		responseDetails := singleUTXOdetails{int(detail.BlockNumber), int(detail.TransactionNumber),
-			int(detail.OutputNumber), detail.Value}
-		details[i] = responseDetails
+			int(detail.OutputNumber), detail.Value, utxoStateSpendable}
+		if utxo.ExitPending {
+			responseDetails.State = utxoStateExitPending
+		}
+		page.UTXOs[i] = responseDetails
 	}
-	writeFasthttpResponse(ctx, details)
-	return
+	if len(utxos) != 0 {
+		page.Cursor = encodeUTXOCursor(utxos[len(utxos)-1].Index)
+	}
+	return page, nil
+}
+
+// encodeUTXOCursor points right after the given output of any address
+func encodeUTXOCursor(index [transaction.UTXOIndexLength]byte) string {
+	raw := append([]byte{utxoCursorVersion}, index[transaction.AddressLength:]...)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeUTXOCursor(cursor string) ([transaction.UTXOIndexLength]byte, bool) {
+	index := [transaction.UTXOIndexLength]byte{}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(raw) != 1+transaction.UTXOIndexLength-transaction.AddressLength || raw[0] != utxoCursorVersion {
+		return index, false
+	}
+	copy(index[transaction.AddressLength:], raw[1:])
+	return index, true
 }
 
 func writeEmptyResponse(w http.ResponseWriter) {
-	response := listUTXOsResponse{false, []singleUTXOdetails{}}
+	response := listUTXOsResponse{false, listUTXOsPage{UTXOs: []singleUTXOdetails{}}}
 	json.NewEncoder(w).Encode(response)
 
 }
 
 func writeResponse(w http.ResponseWriter, details []singleUTXOdetails) {
-	response := listUTXOsResponse{false, details}
+	response := listUTXOsResponse{false, listUTXOsPage{UTXOs: details}}
 	json.NewEncoder(w).Encode(response)
 }
 
-func writeEmptyFasthttpResponse(ctx *fasthttp.RequestCtx) {
-	response := listUTXOsResponse{false, []singleUTXOdetails{}}
-	ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
-	ctx.Response.Header.Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-	ctx.Response.Header.Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-	ctx.SetContentType("application/json")
-	ctx.SetStatusCode(fasthttp.StatusOK)
-	body, _ := json.Marshal(response)
-	ctx.SetBody(body)
-
-}
-
-func writeFasthttpResponse(ctx *fasthttp.RequestCtx, details []singleUTXOdetails) {
-	response := listUTXOsResponse{false, details}
-	ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
-	ctx.Response.Header.Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-	ctx.Response.Header.Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-	ctx.SetContentType("application/json")
-	ctx.SetStatusCode(fasthttp.StatusOK)
-	body, _ := json.Marshal(response)
-	ctx.SetBody(body)
+func writeFasthttpResponse(ctx *fasthttp.RequestCtx, page *listUTXOsPage) {
+	response := listUTXOsResponse{false, *page}
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
 }
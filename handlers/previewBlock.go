@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/matterinc/PlasmaBlockCreator/sequencer"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	"github.com/valyala/fasthttp"
+)
+
+type previewBlockResponse struct {
+	Error             bool   `json:"error"`
+	BlockNumber       uint32 `json:"blockNumber"`
+	PreviousBlockHash string `json:"previousBlockHash"`
+	Transactions      int    `json:"transactions"`
+	Deferred          int    `json:"deferred"`
+	TotalValue        string `json:"totalValue"`
+	MerkleRoot        string `json:"merkleRoot,omitempty"`
+	FirstCounter      uint64 `json:"firstCounter,omitempty"`
+	LastCounter       uint64 `json:"lastCounter,omitempty"`
+	SequencerCounter  uint64 `json:"sequencerCounter"`
+}
+
+var (
+	errPreviewFailed = apiError{"preview_failed", "failed to preview block", fasthttp.StatusServiceUnavailable, true, 0}
+	errParentUnknown = apiError{"parent_unknown", "last written block is not archived, BLOCK_PARENT_HASH is not set", fasthttp.StatusInternalServerError, false, 0}
+)
+
+// PreviewBlockHandler shows what /assembleBlock would put into the next block, the
+// sequencer is not advanced and nothing is signed
+type PreviewBlockHandler struct {
+	db             storage.Database
+	sequencer      sequencer.Sequencer
+	blockAssembler *foundationdb.BlockAssembler
+}
+
+func NewPreviewBlockHandler(db storage.Database, sequencer sequencer.Sequencer) *PreviewBlockHandler {
+	creator := foundationdb.NewBlockAssembler(db, sequencer)
+	handler := &PreviewBlockHandler{db, sequencer, creator}
+	return handler
+}
+
+func (h *PreviewBlockHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
+	blockNumber, previousHash, err := foundationdb.NextBlockToAssemble(h.db)
+	if err == foundationdb.ErrLastBlockNotArchived {
+		writeAPIError(ctx, &errParentUnknown)
+		return
+	}
+	if err != nil {
+		writeStorageUnavailable(ctx, "failed to read last written block")
+		return
+	}
+	preview, err := h.blockAssembler.PreviewBlock(blockNumber, previousHash)
+	if err != nil {
+		writeAPIError(ctx, &errPreviewFailed)
+		return
+	}
+	counter, err := h.sequencer.Current()
+	if err != nil {
+		writeStorageUnavailable(ctx, "failed to read sequencer")
+		return
+	}
+	response := previewBlockResponse{Error: false,
+		BlockNumber:       preview.BlockNumber,
+		PreviousBlockHash: common.ToHex(previousHash),
+		Transactions:      preview.Transactions,
+		Deferred:          preview.Deferred,
+		TotalValue:        preview.TotalValue.String(),
+		FirstCounter:      preview.FirstCounter,
+		LastCounter:       preview.LastCounter,
+		SequencerCounter:  counter}
+	if preview.MerkleRoot != nil {
+		response.MerkleRoot = common.ToHex(preview.MerkleRoot)
+	}
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
+	return
+}
@@ -4,11 +4,11 @@ import (
 	"encoding/json"
 	"strconv"
 
-	"github.com/matterinc/PlasmaCommons/types"
+	"github.com/matterinc/PlasmaBlockCreator/router"
 	"github.com/valyala/fasthttp"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
 	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 )
 
 type depositWithdrawTXrequest struct {
@@ -26,10 +26,10 @@ type depositWithdrawAction struct {
 }
 
 type DepositWithdrawTXHandler struct {
-	db *fdb.Database
+	db storage.Database
 }
 
-func NewDepositWithdrawTXHandler(db *fdb.Database) *DepositWithdrawTXHandler {
+func NewDepositWithdrawTXHandler(db storage.Database) *DepositWithdrawTXHandler {
 	handler := &DepositWithdrawTXHandler{db}
 	return handler
 }
@@ -38,28 +38,23 @@ func (h *DepositWithdrawTXHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
 	var requestJSON depositWithdrawTXrequest
 	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
 	if err != nil {
-		writeDepositWithdrawResponse(ctx, false)
+		writeInvalidRequest(ctx, "invalid request")
+		return
+	}
+	depositIndex, ok := parseUnsignedBigInt(requestJSON.Index)
+	if !ok {
+		writeInvalidRequest(ctx, "invalid deposit index")
 		return
 	}
-	depositIndex := types.NewBigInt(0)
-	depositIndex.SetString(requestJSON.Index, 10)
 	information, err := foundationdb.LookupDepositIndex(h.db, depositIndex)
 	if err != nil {
-		writeDepositWithdrawResponse(ctx, false)
+		writeNotFound(ctx, "deposit not found")
 		return
 	}
 	writeDepositWithdrawChallengeRequiredResponse(ctx, information)
 	return
 }
 
-func writeDepositWithdrawResponse(ctx *fasthttp.RequestCtx, result bool) {
-	response := depositWithdrawTXresponse{!result, nil}
-	ctx.SetContentType("application/json")
-	ctx.SetStatusCode(fasthttp.StatusOK)
-	body, _ := json.Marshal(response)
-	ctx.SetBody(body)
-}
-
 func writeDepositWithdrawChallengeRequiredResponse(ctx *fasthttp.RequestCtx, lookup *foundationdb.DepositLookupResult) {
 	action := &depositWithdrawAction{
 		strconv.Itoa(lookup.BlockNumber),
@@ -67,8 +62,5 @@ func writeDepositWithdrawChallengeRequiredResponse(ctx *fasthttp.RequestCtx, loo
 	}
 	response := depositWithdrawTXresponse{false,
 		action}
-	ctx.SetContentType("application/json")
-	ctx.SetStatusCode(fasthttp.StatusOK)
-	body, _ := json.Marshal(response)
-	ctx.SetBody(body)
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
 }
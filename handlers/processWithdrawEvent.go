@@ -4,13 +4,15 @@ import (
 	"encoding/json"
 	"strconv"
 
+	"github.com/matterinc/PlasmaBlockCreator/events"
+	"github.com/matterinc/PlasmaBlockCreator/router"
 	"github.com/matterinc/PlasmaCommons/transaction"
 	"github.com/matterinc/PlasmaCommons/types"
 	"github.com/valyala/fasthttp"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
 	common "github.com/ethereum/go-ethereum/common"
 	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 )
 
 type withdrawTXrequest struct {
@@ -30,13 +32,14 @@ type withdrawTXaction struct {
 }
 
 type WithdrawTXHandler struct {
-	db               *fdb.Database
+	db               storage.Database
 	txWithdrawMarker *foundationdb.WithdrawTXMarker
+	publisher        events.Publisher
 }
 
-func NewWithdrawTXHandler(db *fdb.Database) *WithdrawTXHandler {
+func NewWithdrawTXHandler(db storage.Database, publisher events.Publisher) *WithdrawTXHandler {
 	marker := foundationdb.NewWithdrawTXMarker(db)
-	handler := &WithdrawTXHandler{db, marker}
+	handler := &WithdrawTXHandler{db, marker, publisher}
 	return handler
 }
 
@@ -44,42 +47,56 @@ func (h *WithdrawTXHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
 	var requestJSON withdrawTXrequest
 	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
 	if err != nil {
-		writeWithdrawResponse(ctx, false)
+		writeInvalidRequest(ctx, "invalid request")
 		return
 	}
 	to := common.Address{}
 	toBytes := common.FromHex(requestJSON.For)
 	if len(toBytes) != transaction.AddressLength {
-		writeWithdrawResponse(ctx, false)
+		writeInvalidRequest(ctx, "invalid address")
 		return
 	}
 	copy(to[:], toBytes)
-	utxoIndex := types.NewBigInt(0)
-	utxoIndex.SetString(requestJSON.Index, 10)
+	utxoIndex, ok := parseUnsignedBigInt(requestJSON.Index)
+	if !ok {
+		writeInvalidRequest(ctx, "invalid index")
+		return
+	}
 	success, err := h.txWithdrawMarker.MarkTX(to, utxoIndex)
 	if err != nil {
-		writeWithdrawResponse(ctx, false)
+		writeStorageUnavailable(ctx, "failed to mark UTXO for withdrawal")
 		return
 	}
 	if success != true {
 		lookup, err := foundationdb.LookupSpendingIndex(h.db, utxoIndex)
 		if err != nil {
-			writeWithdrawResponse(ctx, false)
+			writeNotFound(ctx, "spending transaction not found")
 			return
 		}
 		writeWithdrawChallengeRequiredResponse(ctx, lookup)
 		return
 	}
+	h.publishExitStarted(to, utxoIndex)
 	writeWithdrawResponse(ctx, true)
 	return
 }
 
+// publishExitStarted has no value, the marker only knows the number of the output
+func (h *WithdrawTXHandler) publishExitStarted(to common.Address, utxoIndex *types.BigInt) {
+	details, err := transaction.ParseUTXOindexNumberIntoDetails(utxoIndex)
+	if err != nil {
+		return
+	}
+	h.publisher.Publish(&events.Event{Topic: events.AddressTopic(to),
+		Type:              events.UTXOExitStarted,
+		BlockNumber:       details.BlockNumber,
+		TransactionNumber: details.TransactionNumber,
+		OutputNumber:      details.OutputNumber})
+}
+
 func writeWithdrawResponse(ctx *fasthttp.RequestCtx, result bool) {
 	response := withdrawTXresponse{!result, nil}
-	ctx.SetContentType("application/json")
-	ctx.SetStatusCode(fasthttp.StatusOK)
-	body, _ := json.Marshal(response)
-	ctx.SetBody(body)
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
 }
 
 func writeWithdrawChallengeRequiredResponse(ctx *fasthttp.RequestCtx, lookup *foundationdb.SpendingLookupResult) {
@@ -91,8 +108,5 @@ func writeWithdrawChallengeRequiredResponse(ctx *fasthttp.RequestCtx, lookup *fo
 	response := withdrawTXresponse{false,
 		action,
 	}
-	ctx.SetContentType("application/json")
-	ctx.SetStatusCode(fasthttp.StatusOK)
-	body, _ := json.Marshal(response)
-	ctx.SetBody(body)
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
 }
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"github.com/matterinc/PlasmaBlockCreator/router"
+)
+
+// Routes are the HTTP handlers of a binary, Register adds a route for every handler that is set
+type Routes struct {
+	SendRawTX            *SendRawTXHandler
+	SendRawTXs           *SendRawTXsHandler
+	JSONRPC              *JSONRPCHandler
+	ListUTXOs            *ListUTXOsHandler
+	GetTransaction       *GetTransactionHandler
+	GetTransactionStatus *GetTransactionStatusHandler
+	GetBlock             *GetBlockHandler
+	GetBlockHeader       *GetBlockHeaderHandler
+	GetProof             *GetProofHandler
+	GetBalance           *GetBalanceHandler
+	Subscribe            *SubscribeHandler
+	AssembleBlock        *AssembleBlockHandler
+	PreviewBlock         *PreviewBlockHandler
+	WriteBlock           *WriteBlockHandler
+	LastBlock            *LastBlockHandler
+	Deposit              *CreateFundingTXHandler
+	Exit                 *WithdrawTXHandler
+	DepositExit          *DepositWithdrawTXHandler
+	// LegacyFundingTX is the deposit handler under its old path
+	LegacyFundingTX *CreateFundingTXHandler
+	// CreateUTXO creates outputs out of nothing, it should be set in dev mode only
+	CreateUTXO *CreateUTXOHandler
+}
+
+// Register adds the routes to a router. JSON bodies are checked with validate unless it is nil,
+// /sendRawTX and /rpc answer malformed requests in their own formats. Routes that write blocks
+// or create outputs are passed through operatorAuth first, nil leaves them open
+func (routes *Routes) Register(r *router.Router, validate router.Middleware, operatorAuth router.Middleware) {
+	jsonBody := []router.Middleware{router.BodyLimit(MaxJSONBodySize)}
+	batchBody := []router.Middleware{router.BodyLimit(MaxSendRawTXBatchBodySize)}
+	if validate != nil {
+		jsonBody = append(jsonBody, validate)
+		batchBody = append(batchBody, validate)
+	}
+	operator := []router.Middleware{}
+	operatorJSONBody := jsonBody
+	if operatorAuth != nil {
+		operator = []router.Middleware{operatorAuth}
+		operatorJSONBody = append([]router.Middleware{operatorAuth}, jsonBody...)
+	}
+	if routes.SendRawTX != nil {
+		r.POST("/sendRawTX", routes.SendRawTX.HandlerFunc, router.BodyLimit(MaxJSONBodySize))
+	}
+	if routes.SendRawTXs != nil {
+		r.POST("/sendRawTXs", routes.SendRawTXs.HandlerFunc, batchBody...)
+	}
+	if routes.CreateUTXO != nil {
+		r.POST("/createUTXO", routes.CreateUTXO.HandlerFunc, operatorJSONBody...)
+	}
+	if routes.ListUTXOs != nil {
+		r.POST("/listUTXOs", routes.ListUTXOs.HandlerFunc, jsonBody...)
+	}
+	if routes.GetTransaction != nil {
+		r.POST("/getTransaction", routes.GetTransaction.HandlerFunc, jsonBody...)
+	}
+	if routes.GetTransactionStatus != nil {
+		r.POST("/getTransactionStatus", routes.GetTransactionStatus.HandlerFunc, jsonBody...)
+	}
+	if routes.GetBlock != nil {
+		r.POST("/getBlock", routes.GetBlock.HandlerFunc, jsonBody...)
+	}
+	if routes.GetBlockHeader != nil {
+		r.POST("/getBlockHeader", routes.GetBlockHeader.HandlerFunc, jsonBody...)
+	}
+	if routes.GetProof != nil {
+		r.POST("/getProof", routes.GetProof.HandlerFunc, jsonBody...)
+	}
+	if routes.GetBalance != nil {
+		r.POST("/getBalance", routes.GetBalance.HandlerFunc, jsonBody...)
+	}
+	if routes.Subscribe != nil {
+		r.GET("/subscribe", routes.Subscribe.HandlerFunc)
+	}
+	if routes.JSONRPC != nil {
+		r.POST("/rpc", routes.JSONRPC.HandlerFunc, router.BodyLimit(MaxJSONRPCBodySize))
+	}
+	if routes.AssembleBlock != nil {
+		r.POST("/assembleBlock", routes.AssembleBlock.HandlerFunc, operatorJSONBody...)
+	}
+	if routes.PreviewBlock != nil {
+		r.GET("/previewBlock", routes.PreviewBlock.HandlerFunc, operator...)
+	}
+	if routes.LegacyFundingTX != nil {
+		r.POST("/createFundingTX", routes.LegacyFundingTX.HandlerFunc, operatorJSONBody...)
+	}
+	if routes.LastBlock != nil {
+		r.GET("/lastWrittenBlock", routes.LastBlock.HandlerFunc)
+		r.POST("/lastWrittenBlock", routes.LastBlock.HandlerFunc)
+	}
+	if routes.WriteBlock != nil {
+		r.POST("/writeBlock", routes.WriteBlock.HandlerFunc, operator...)
+	}
+	if routes.Deposit != nil {
+		r.POST("/processEvent/DepositEvent", routes.Deposit.HandlerFunc, operatorJSONBody...)
+	}
+	if routes.Exit != nil {
+		r.POST("/processEvent/ExitStartedEvent", routes.Exit.HandlerFunc, operatorJSONBody...)
+	}
+	if routes.DepositExit != nil {
+		r.POST("/processEvent/DepositWithdrawStartedEvent", routes.DepositExit.HandlerFunc, operatorJSONBody...)
+	}
+}
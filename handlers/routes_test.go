@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/matterinc/PlasmaBlockCreator/openapi"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+)
+
+func TestRoutesMatchSpec(t *testing.T) {
+	spec, err := openapi.Load("../docs/plasma.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := Routes{SendRawTX: &SendRawTXHandler{},
+		SendRawTXs:           &SendRawTXsHandler{},
+		JSONRPC:              &JSONRPCHandler{},
+		ListUTXOs:            &ListUTXOsHandler{},
+		GetTransaction:       &GetTransactionHandler{},
+		GetTransactionStatus: &GetTransactionStatusHandler{},
+		GetBlock:             &GetBlockHandler{},
+		GetBlockHeader:       &GetBlockHeaderHandler{},
+		GetProof:             &GetProofHandler{},
+		GetBalance:           &GetBalanceHandler{},
+		Subscribe:            &SubscribeHandler{},
+		AssembleBlock:        &AssembleBlockHandler{},
+		PreviewBlock:         &PreviewBlockHandler{},
+		WriteBlock:           &WriteBlockHandler{},
+		LastBlock:            &LastBlockHandler{},
+		Deposit:              &CreateFundingTXHandler{},
+		Exit:                 &WithdrawTXHandler{},
+		DepositExit:          &DepositWithdrawTXHandler{},
+		LegacyFundingTX:      &CreateFundingTXHandler{},
+		CreateUTXO:           &CreateUTXOHandler{}}
+	r := router.New()
+	routes.Register(r, openapi.Validate(spec), nil)
+	documented := make(map[router.Route]bool)
+	for _, route := range spec.Routes() {
+		documented[route] = true
+	}
+	for _, route := range r.Routes() {
+		if !documented[route] {
+			t.Error("Route " + route.Method + " " + route.Path + " is missing in docs/plasma.yaml")
+		}
+		delete(documented, route)
+	}
+	for route := range documented {
+		t.Error("Route " + route.Method + " " + route.Path + " is documented but not registered")
+	}
+}
@@ -2,12 +2,18 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
 	common "github.com/ethereum/go-ethereum/common"
-	redis "github.com/go-redis/redis"
+	"github.com/matterinc/PlasmaBlockCreator/events"
 	foundationdb "github.com/matterinc/PlasmaBlockCreator/foundationdb"
 	"github.com/matterinc/PlasmaBlockCreator/policy"
+	"github.com/matterinc/PlasmaBlockCreator/ratelimit"
+	"github.com/matterinc/PlasmaBlockCreator/rejections"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/matterinc/PlasmaBlockCreator/sequencer"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	transaction "github.com/matterinc/PlasmaCommons/transaction"
 	"github.com/valyala/fasthttp"
 )
@@ -17,68 +23,205 @@ type sendRawRLPTXRequest struct {
 }
 
 type sendRawRLPTXResponse struct {
-	Error    bool   `json:"error"`
-	Accepted bool   `json:"accepted,omitempty"`
-	Reason   string `json:"reason,omitempty"`
+	Error     bool   `json:"error"`
+	Accepted  bool   `json:"accepted,omitempty"`
+	Code      string `json:"code,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Retryable bool   `json:"retryable,omitempty"`
+	// RetryAfter is in seconds, only for rate_limited
+	RetryAfter int    `json:"retryAfter,omitempty"`
+	Hash       string `json:"hash,omitempty"`
+	Counter    uint64 `json:"counter,omitempty"`
 }
 
 type SendRawTXHandler struct {
-	db          *fdb.Database
-	redisClient *redis.Client
-	utxoReader  *foundationdb.UTXOReader
-	utxoWriter  *foundationdb.UTXOWriter
-	parser      *transaction.TransactionParser
+	db         storage.Database
+	sequencer  sequencer.Sequencer
+	utxoReader *foundationdb.UTXOReader
+	utxoWriter *foundationdb.UTXOWriter
+	parser     *transaction.TransactionParser
+	publisher  events.Publisher
+	rejected   rejections.Store
+	// perClient and perSender are nil unless SetRateLimits is called
+	perClient *ratelimit.Limiter
+	perSender *ratelimit.Limiter
 }
 
-func NewSendRawTXHandler(db *fdb.Database, redisClient *redis.Client, parser *transaction.TransactionParser, writerConcurrency int) *SendRawTXHandler {
+func NewSendRawTXHandler(db storage.Database, sequencer sequencer.Sequencer, parser *transaction.TransactionParser, writerConcurrency int, publisher events.Publisher, rejected rejections.Store) *SendRawTXHandler {
 	reader := foundationdb.NewUTXOReader(db)
 	writer := foundationdb.NewUTXOWriter(db, writerConcurrency)
-	handler := &SendRawTXHandler{db, redisClient, reader, writer, parser}
+	handler := &SendRawTXHandler{db, sequencer, reader, writer, parser, publisher, rejected, nil, nil}
 	return handler
 }
 
+// SetRateLimits limits submissions per client IP and per sender address, a nil limiter
+// leaves the key unlimited
+func (h *SendRawTXHandler) SetRateLimits(perClient *ratelimit.Limiter, perSender *ratelimit.Limiter) {
+	h.perClient = perClient
+	h.perSender = perSender
+}
+
 func (h *SendRawTXHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
+	var hash []byte
+	var counter uint64
+	bytes, failure := readRawTransaction(ctx)
+	if failure == nil {
+		hash, counter, failure = h.submit(router.ClientIP(ctx), bytes)
+	}
+	if acceptsCBOR(ctx) {
+		writeSendRawTXCBORResponse(ctx, hash, counter, failure)
+		return
+	}
+	if failure != nil {
+		writeSendRawTXErrorResponse(ctx, *failure)
+		return
+	}
+	writeSendRawTXSuccessResponse(ctx, hash, counter)
+	return
+}
+
+// readRawTransaction takes an octet-stream body as RLP bytes, any other body as JSON with a hex string
+func readRawTransaction(ctx *fasthttp.RequestCtx) ([]byte, *apiError) {
+	if hasRawTransactionBody(ctx) {
+		if len(ctx.PostBody()) == 0 {
+			return nil, &errInvalidEncoding
+		}
+		// the body is reused by fasthttp after the request
+		return append([]byte{}, ctx.PostBody()...), nil
+	}
 	var requestJSON sendRawRLPTXRequest
 	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
 	if err != nil {
-		writeFasthttpErrorResponse(ctx)
-		return
+		return nil, &errInvalidRequest
 	}
 	bytes := common.FromHex(requestJSON.TX)
 	if bytes == nil || len(bytes) == 0 {
-		writeFasthttpErrorResponse(ctx)
-		return
+		return nil, &errInvalidEncoding
+	}
+	return bytes, nil
+}
+
+// submit checks a raw transaction from a client and writes its spending record. The counter
+// is zero if the place in a block is only known after commit, clients poll by hash then
+func (h *SendRawTXHandler) submit(client string, bytes []byte) ([]byte, uint64, *apiError) {
+	failure := h.limitClient(client)
+	if failure != nil {
+		return nil, 0, failure
 	}
 	parsedRes, err := h.parser.Parse(bytes)
 	if err != nil {
-		writeFasthttpErrorResponse(ctx)
-		return
+		return nil, 0, &errInvalidTransaction
 	}
-	err = policy.CheckForPolicy(&parsedRes.TX)
+	hash, raw, err := foundationdb.TransactionHash(&parsedRes.TX)
 	if err != nil {
-		writeFasthttpErrorResponse(ctx)
-		return
+		return nil, 0, &errInvalidTransaction
 	}
-	err = h.utxoReader.CheckIfUTXOsExist(&parsedRes.TX)
+	return h.submitParsed(parsedRes, hash, len(raw))
+}
+
+// submitParsed runs the checks and the write for a transaction with a valid signature,
+// size is its encoded length that counts against the block size limit
+func (h *SendRawTXHandler) submitParsed(parsedRes *transaction.ParsedTransactionResult, hash []byte, size int) ([]byte, uint64, *apiError) {
+	// limited transactions are not marked as rejected, they may be sent again later
+	failure := h.limitSender(&parsedRes.TX)
+	if failure != nil {
+		return nil, 0, failure
+	}
+	err := policy.CheckForPolicy(&parsedRes.TX)
 	if err != nil {
-		writeFasthttpErrorResponse(ctx)
-		return
+		failure := errPolicyViolation.withMessage(err.Error())
+		return nil, 0, h.reject(hash, err, failure)
+	}
+	err = h.utxoReader.CheckIfUTXOsExist(&parsedRes.TX)
+	if err == foundationdb.ErrFundingTransaction {
+		return nil, 0, h.reject(hash, err, errFundingTransaction)
+	} else if err == foundationdb.ErrUTXONotFound {
+		return nil, 0, h.reject(hash, err, errUTXONotFound)
+	} else if err != nil {
+		return nil, 0, &errStorageUnavailable
+	}
+	if sequencer.AssignedOnCommit(h.sequencer) {
+		err = h.utxoWriter.WriteSpendingInOpenBlock(parsedRes)
+		if err != nil {
+			return nil, 0, h.spendingFailed(hash, err)
+		}
+		h.publishSpent(&parsedRes.TX, hash)
+		return hash, 0, nil
 	}
 	// one can get a counter from a centralized storage
-	counter, err := h.redisClient.Incr("ctr").Result()
+	counter, err := h.sequencer.Next(size)
 	if err != nil {
-		writeFasthttpErrorResponse(ctx)
-		return
+		return nil, 0, &errSequencerUnavailable
 	}
 
 	// // one can play with local atomic counter
 	// counter := commonTools.GetCounter()
 
-	err = h.utxoWriter.WriteSpending(parsedRes, uint64(counter))
+	err = h.utxoWriter.WriteSpending(parsedRes, counter)
 	if err != nil {
-		writeFasthttpErrorResponse(ctx)
-		return
+		return nil, 0, h.spendingFailed(hash, err)
 	}
-	writeFasthttpSuccessResponse(ctx)
-	return
+	h.publishSpent(&parsedRes.TX, hash)
+	return hash, counter, nil
+}
+
+// limitClient is checked before parsing, so the signature check is not spent on limited clients
+func (h *SendRawTXHandler) limitClient(client string) *apiError {
+	if h.perClient == nil {
+		return nil
+	}
+	ok, retryAfter := h.perClient.Take(client)
+	if !ok {
+		return rateLimited("too many transactions from this client", retryAfter)
+	}
+	return nil
+}
+
+// limitSender is checked before a counter is taken, so limited transactions leave no gaps.
+// The sender is not recovered if senders are unlimited
+func (h *SendRawTXHandler) limitSender(tx *transaction.SignedTransaction) *apiError {
+	if h.perSender == nil {
+		return nil
+	}
+	from, err := tx.GetFrom()
+	if err != nil {
+		return &errInvalidTransaction
+	}
+	ok, retryAfter := h.perSender.Take(strings.ToLower(from.Hex()))
+	if !ok {
+		return rateLimited("too many transactions from this sender", retryAfter)
+	}
+	return nil
+}
+
+// publishSpent notifies the owner of the inputs of an accepted transaction
+func (h *SendRawTXHandler) publishSpent(tx *transaction.SignedTransaction, hash []byte) {
+	for i := range tx.UnsignedTransaction.Inputs {
+		index, err := transaction.CreateCorrespondingUTXOIndexForInput(tx, i)
+		if err != nil {
+			return
+		}
+		h.publisher.Publish(events.NewUTXOEvent(events.UTXOSpent, index, hash))
+	}
+}
+
+func (h *SendRawTXHandler) spendingFailed(hash []byte, err error) *apiError {
+	switch err {
+	case foundationdb.ErrDoubleSpend:
+		return h.reject(hash, err, errDoubleSpend)
+	case foundationdb.ErrTransactionExists:
+		// the transaction itself is fine, only the counter was taken
+		return &errCounterConflict
+	default:
+		return &errStorageUnavailable
+	}
+}
+
+// reject remembers the reason so the status of the transaction can be polled later
+func (h *SendRawTXHandler) reject(hash []byte, reason error, failure apiError) *apiError {
+	err := h.rejected.Reject(hash, reason.Error())
+	if err != nil {
+		fmt.Println("Failed to mark transaction as rejected: " + err.Error())
+	}
+	return &failure
 }
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/valyala/fasthttp"
+)
+
+var (
+	errInvalidRequest       = apiError{"invalid_request", "request is not a valid JSON", fasthttp.StatusBadRequest, false, 0}
+	errInvalidEncoding      = apiError{"invalid_encoding", "transaction is not a hex encoded string", fasthttp.StatusBadRequest, false, 0}
+	errInvalidTransaction   = apiError{"invalid_transaction", "transaction can not be parsed or has an invalid signature", fasthttp.StatusBadRequest, false, 0}
+	errFundingTransaction   = apiError{"funding_transaction", "funding transactions can not be submitted", fasthttp.StatusBadRequest, false, 0}
+	errPolicyViolation      = apiError{"policy_violation", "transaction violates the operator policy", fasthttp.StatusUnprocessableEntity, false, 0}
+	errUTXONotFound         = apiError{"utxo_not_found", "one of the inputs does not exist or is not spendable", fasthttp.StatusUnprocessableEntity, false, 0}
+	errDoubleSpend          = apiError{"double_spend", "one of the inputs was spent by another transaction", fasthttp.StatusConflict, false, 0}
+	errBatchConflict        = apiError{"batch_conflict", "transaction conflicts with an earlier one in the same batch", fasthttp.StatusConflict, false, 0}
+	errCounterConflict      = apiError{"counter_conflict", "transaction counter was already used, submit again", fasthttp.StatusConflict, true, 0}
+	errSequencerUnavailable = apiError{"sequencer_unavailable", "failed to assign a transaction counter", fasthttp.StatusServiceUnavailable, true, 0}
+	errStorageUnavailable   = apiError{"storage_unavailable", "failed to access the storage", fasthttp.StatusServiceUnavailable, true, 0}
+	errRateLimited          = apiError{"rate_limited", "too many transactions, retry later", fasthttp.StatusTooManyRequests, true, 0}
+)
+
+// rateLimited tells when a limited client or sender gets its next token
+func rateLimited(message string, retryAfter time.Duration) *apiError {
+	failure := errRateLimited.withMessage(message)
+	failure.RetryAfter = retryAfter
+	return &failure
+}
+
+// writeSendRawTXErrorResponse also tells whether submitting the same transaction again may help
+func writeSendRawTXErrorResponse(ctx *fasthttp.RequestCtx, e apiError) {
+	response := sendRawRLPTXResponse{Error: true, Code: e.Code, Reason: e.Message, Retryable: e.Retryable, RetryAfter: e.retryAfterSeconds()}
+	router.WriteJSON(ctx, e.StatusCode, response)
+	setRetryAfter(ctx, &e)
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	transaction "github.com/matterinc/PlasmaCommons/transaction"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// MaxSendRawTXBatchSize limits the number of transactions in one batch
+	MaxSendRawTXBatchSize = 1000
+	// MaxSendRawTXBatchBodySize fits a full batch of hex encoded transactions
+	MaxSendRawTXBatchBodySize = MaxSendRawTXBatchSize * MaxJSONBodySize
+)
+
+type sendRawTXsRequest struct {
+	TXs []string `json:"txs"`
+}
+
+// sendRawTXResult is reported for every transaction of a batch in the same order
+type sendRawTXResult struct {
+	Accepted  bool   `json:"accepted"`
+	Code      string `json:"code,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Retryable bool   `json:"retryable,omitempty"`
+	// RetryAfter is in seconds, only for rate_limited
+	RetryAfter int    `json:"retryAfter,omitempty"`
+	Hash       string `json:"hash,omitempty"`
+	Counter    uint64 `json:"counter,omitempty"`
+}
+
+type sendRawTXsResponse struct {
+	Error    bool              `json:"error"`
+	Accepted int               `json:"accepted"`
+	Results  []sendRawTXResult `json:"results"`
+}
+
+// batchItem is a transaction of a batch on its way through parsing, conflict checks and writing
+type batchItem struct {
+	parsed  *transaction.ParsedTransactionResult
+	hash    []byte
+	size    int
+	counter uint64
+	failure *apiError
+}
+
+// SendRawTXsHandler accepts many transactions in one request. Every one of them is checked
+// and written on its own, so a batch may be accepted partially
+type SendRawTXsHandler struct {
+	sendRawTX *SendRawTXHandler
+}
+
+func NewSendRawTXsHandler(sendRawTX *SendRawTXHandler) *SendRawTXsHandler {
+	handler := &SendRawTXsHandler{sendRawTX}
+	return handler
+}
+
+func (h *SendRawTXsHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
+	var requestJSON sendRawTXsRequest
+	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
+	if err != nil {
+		writeSendRawTXErrorResponse(ctx, errInvalidRequest)
+		return
+	}
+	if len(requestJSON.TXs) == 0 {
+		writeSendRawTXErrorResponse(ctx, errInvalidRequest.withMessage("empty batch"))
+		return
+	}
+	if len(requestJSON.TXs) > MaxSendRawTXBatchSize {
+		writeSendRawTXErrorResponse(ctx, errInvalidRequest.withMessage("batch is larger than "+strconv.Itoa(MaxSendRawTXBatchSize)+" transactions"))
+		return
+	}
+	raws := make([][]byte, len(requestJSON.TXs))
+	for i, tx := range requestJSON.TXs {
+		raws[i] = common.FromHex(tx)
+	}
+	items := h.submitBatch(router.ClientIP(ctx), raws)
+	response := sendRawTXsResponse{Error: false, Results: make([]sendRawTXResult, len(items))}
+	// the header tells when all limited transactions of the batch may be sent again
+	var limited *apiError
+	for i, item := range items {
+		response.Results[i] = newSendRawTXResult(item)
+		if item.failure == nil {
+			response.Accepted++
+		} else if limited == nil || item.failure.RetryAfter > limited.RetryAfter {
+			limited = item.failure
+		}
+	}
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
+	if limited != nil {
+		setRetryAfter(ctx, limited)
+	}
+	return
+}
+
+// submitBatch parses all transactions first, so conflicts inside the batch are found
+// before any of them is written. Every transaction takes a token of the client
+func (h *SendRawTXsHandler) submitBatch(client string, raws [][]byte) []*batchItem {
+	items := make([]*batchItem, len(raws))
+	limited := make([]*apiError, len(raws))
+	for i := range raws {
+		limited[i] = h.sendRawTX.limitClient(client)
+	}
+	h.parallel(items, func(i int) *batchItem {
+		if limited[i] != nil {
+			return &batchItem{failure: limited[i]}
+		}
+		return h.parse(raws[i])
+	})
+	markBatchConflicts(items)
+	h.parallel(items, func(i int) *batchItem {
+		item := items[i]
+		if item.failure != nil {
+			return item
+		}
+		_, item.counter, item.failure = h.sendRawTX.submitParsed(item.parsed, item.hash, item.size)
+		return item
+	})
+	return items
+}
+
+func (h *SendRawTXsHandler) parse(raw []byte) *batchItem {
+	if len(raw) == 0 {
+		return &batchItem{failure: &errInvalidEncoding}
+	}
+	// the parser limits the number of signatures checked at once
+	parsedRes, err := h.sendRawTX.parser.Parse(raw)
+	if err != nil {
+		return &batchItem{failure: &errInvalidTransaction}
+	}
+	hash, encoded, err := foundationdb.TransactionHash(&parsedRes.TX)
+	if err != nil {
+		return &batchItem{failure: &errInvalidTransaction}
+	}
+	return &batchItem{parsed: parsedRes, hash: hash, size: len(encoded)}
+}
+
+func (h *SendRawTXsHandler) parallel(items []*batchItem, f func(i int) *batchItem) {
+	var wg sync.WaitGroup
+	for i := range items {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			items[i] = f(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// markBatchConflicts fails every transaction that repeats an earlier one or spends
+// an input of it, the earliest one is left to the usual checks
+func markBatchConflicts(items []*batchItem) {
+	hashes := make(map[string]int)
+	inputs := make(map[string]int)
+	for i, item := range items {
+		if item.failure != nil {
+			continue
+		}
+		if earlier, ok := hashes[string(item.hash)]; ok {
+			failure := errBatchConflict.withMessage("same transaction as item " + strconv.Itoa(earlier))
+			item.failure = &failure
+			continue
+		}
+		hashes[string(item.hash)] = i
+		tx := &item.parsed.TX
+		keys := make([]string, 0, len(tx.UnsignedTransaction.Inputs))
+		for k := range tx.UnsignedTransaction.Inputs {
+			key, err := transaction.CreateShortUTXOIndexForInput(tx, k)
+			if err != nil {
+				item.failure = &errInvalidTransaction
+				break
+			}
+			if earlier, ok := inputs[string(key)]; ok {
+				failure := errBatchConflict.withMessage("spends the same output as item " + strconv.Itoa(earlier))
+				item.failure = &failure
+				break
+			}
+			keys = append(keys, string(key))
+		}
+		if item.failure != nil {
+			continue
+		}
+		for _, key := range keys {
+			inputs[key] = i
+		}
+	}
+}
+
+func newSendRawTXResult(item *batchItem) sendRawTXResult {
+	result := sendRawTXResult{Accepted: item.failure == nil, Counter: item.counter}
+	if len(item.hash) != 0 {
+		result.Hash = common.ToHex(item.hash)
+	}
+	if item.failure != nil {
+		result.Code = item.failure.Code
+		result.Reason = item.failure.Message
+		result.Retryable = item.failure.Retryable
+		result.RetryAfter = item.failure.retryAfterSeconds()
+	}
+	return result
+}
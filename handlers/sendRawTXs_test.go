@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/events"
+	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/ratelimit"
+	"github.com/matterinc/PlasmaBlockCreator/rejections"
+	"github.com/matterinc/PlasmaBlockCreator/sequencer"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	transaction "github.com/matterinc/PlasmaCommons/transaction"
+	"github.com/matterinc/PlasmaCommons/types"
+	"github.com/valyala/fasthttp"
+)
+
+var testOwner = common.HexToAddress("0x627306090abab3a6e1400e9345bc60c78a8bef57")
+var testOwnerKey = common.FromHex("0xc87509a1c067bbde78beb793e6fa76530b6382a4c0241e5e4a9ec0a0f44dc0d3")
+
+// createTestSpend spends output 0 of transaction 0 in a block to a recipient
+func createTestSpend(blockNumber int64, value int64, to common.Address) (string, error) {
+	input := &transaction.TransactionInput{}
+	err := input.SetFields(types.NewBigInt(blockNumber), types.NewBigInt(0), types.NewBigInt(0), types.NewBigInt(value))
+	if err != nil {
+		return "", err
+	}
+	output := &transaction.TransactionOutput{}
+	err = output.SetFields(types.NewBigInt(0), to, types.NewBigInt(value))
+	if err != nil {
+		return "", err
+	}
+	tx, err := transaction.NewUnsignedTransaction(transaction.TransactionTypeSplit,
+		[]*transaction.TransactionInput{input}, []*transaction.TransactionOutput{output})
+	if err != nil {
+		return "", err
+	}
+	emptyBytes := [32]byte{}
+	signed, err := transaction.NewSignedTransaction(tx, []byte{0x00}, emptyBytes[:], emptyBytes[:])
+	if err != nil {
+		return "", err
+	}
+	err = signed.Sign(testOwnerKey)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	err = signed.EncodeRLP(&b)
+	if err != nil {
+		return "", err
+	}
+	return common.ToHex(b.Bytes()), nil
+}
+
+func TestSendRawTXsRejectsInvalidBatches(t *testing.T) {
+	h := NewSendRawTXsHandler(&SendRawTXHandler{})
+	tooLarge := `{"txs": ["0x01"` + strings.Repeat(`, "0x01"`, MaxSendRawTXBatchSize) + `]}`
+	for _, body := range []string{`{"txs": [`, `{"txs": []}`, tooLarge} {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetBodyString(body)
+		h.HandlerFunc(ctx)
+		var response sendRawRLPTXResponse
+		json.Unmarshal(ctx.Response.Body(), &response)
+		if ctx.Response.StatusCode() != fasthttp.StatusBadRequest || !response.Error || response.Code != "invalid_request" {
+			t.Fatal("Batch should be rejected as a whole: " + string(ctx.Response.Body()))
+		}
+	}
+}
+
+func TestSendRawTXsReportsEveryItem(t *testing.T) {
+	h := NewSendRawTXsHandler(&SendRawTXHandler{})
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetBodyString(`{"txs": ["", "0x"]}`)
+	h.HandlerFunc(ctx)
+	var response sendRawTXsResponse
+	err := json.Unmarshal(ctx.Response.Body(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Response.StatusCode() != fasthttp.StatusOK || response.Accepted != 0 || len(response.Results) != 2 {
+		t.Fatal("Every item should have a result")
+	}
+	for _, result := range response.Results {
+		if result.Accepted || result.Code != "invalid_encoding" {
+			t.Fatal("Empty transaction should not be accepted")
+		}
+	}
+}
+
+func TestSendRawTXsLimitsClients(t *testing.T) {
+	sendRawTX := &SendRawTXHandler{}
+	sendRawTX.SetRateLimits(ratelimit.NewLimiter(1, 1), nil)
+	h := NewSendRawTXsHandler(sendRawTX)
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetBodyString(`{"txs": ["", ""]}`)
+	h.HandlerFunc(ctx)
+	var response sendRawTXsResponse
+	json.Unmarshal(ctx.Response.Body(), &response)
+	if len(response.Results) != 2 || response.Results[0].Code != "invalid_encoding" ||
+		response.Results[1].Code != "rate_limited" || response.Results[1].RetryAfter != 1 {
+		t.Fatal("Second item should be limited: " + string(ctx.Response.Body()))
+	}
+	if string(ctx.Response.Header.Peek("Retry-After")) != "1" {
+		t.Fatal("Limited batch should have a Retry-After header")
+	}
+	// the bucket is empty, so the transaction is not parsed and takes no counter
+	ctx = &fasthttp.RequestCtx{}
+	ctx.Request.SetBodyString(`{"tx": "0x01"}`)
+	sendRawTX.HandlerFunc(ctx)
+	var single sendRawRLPTXResponse
+	json.Unmarshal(ctx.Response.Body(), &single)
+	if ctx.Response.StatusCode() != fasthttp.StatusTooManyRequests || single.Code != "rate_limited" ||
+		string(ctx.Response.Header.Peek("Retry-After")) != "1" {
+		t.Fatal("Transaction should be limited: " + string(ctx.Response.Body()))
+	}
+}
+
+func TestSendRawTXsWritesOnlyFirstOfConflicts(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	seq, err := sequencer.NewLocalSequencer(db, sequencer.BlockLimits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = foundationdb.NewTestUTXOcreator(db).InsertUTXO(testOwner, 1, 0, 0, types.NewBigInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := createTestSpend(1, 1000, common.HexToAddress("0xf17f52151ebef6c7334fad080c5704d77216b732"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := createTestSpend(1, 1000, common.HexToAddress("0xc5fdf4076b8f3a5357c5e395ab970b5b54098fef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendRawTX := NewSendRawTXHandler(db, seq, transaction.NewTransactionParser(1), 1, events.Discard,
+		rejections.NewMemoryStore(time.Hour, 10))
+	h := NewSendRawTXsHandler(sendRawTX)
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetBodyString(`{"txs": ["` + first + `", "` + first + `", "` + other + `"]}`)
+	h.HandlerFunc(ctx)
+	var response sendRawTXsResponse
+	err = json.Unmarshal(ctx.Response.Body(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Accepted != 1 || len(response.Results) != 3 || !response.Results[0].Accepted {
+		t.Fatal("Only the first transaction should be accepted: " + string(ctx.Response.Body()))
+	}
+	for _, result := range response.Results[1:] {
+		if result.Accepted || result.Code != "batch_conflict" {
+			t.Fatal("Later transactions should conflict: " + string(ctx.Response.Body()))
+		}
+	}
+	found, err := foundationdb.LookupTransaction(db, common.FromHex(response.Results[0].Hash))
+	if err != nil || !found.Pending {
+		t.Fatal("First transaction should be written")
+	}
+	_, err = foundationdb.LookupTransaction(db, common.FromHex(response.Results[2].Hash))
+	if err == nil {
+		t.Fatal("Conflicting transaction should not be written")
+	}
+}
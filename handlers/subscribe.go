@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"time"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/fasthttp/websocket"
+	"github.com/matterinc/PlasmaBlockCreator/events"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/matterinc/PlasmaCommons/transaction"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	websocketWriteTimeout = 10 * time.Second
+	websocketPongTimeout  = 60 * time.Second
+	websocketPingPeriod   = 50 * time.Second
+	// MaxWebsocketMessageSize limits a single request of a subscriber
+	MaxWebsocketMessageSize = 1024
+)
+
+// subscribeRequest watches either an address or a topic, "blocks" for written blocks
+type subscribeRequest struct {
+	Action  string `json:"action"`
+	Address string `json:"address,omitempty"`
+	Topic   string `json:"topic,omitempty"`
+}
+
+type subscribeResponse struct {
+	Error  bool   `json:"error"`
+	Action string `json:"action"`
+	Topic  string `json:"topic"`
+}
+
+// SubscribeHandler pushes events to WebSocket clients, a client that lags behind is
+// disconnected and should list its UTXOs again after reconnecting
+type SubscribeHandler struct {
+	broker   *events.Broker
+	upgrader websocket.FastHTTPUpgrader
+}
+
+func NewSubscribeHandler(broker *events.Broker) *SubscribeHandler {
+	upgrader := websocket.FastHTTPUpgrader{
+		// the HTTP API is open to any origin as well
+		CheckOrigin: func(ctx *fasthttp.RequestCtx) bool { return true },
+		Error: func(ctx *fasthttp.RequestCtx, status int, reason error) {
+			router.WriteError(ctx, status, "websocket_required", reason.Error())
+		},
+	}
+	handler := &SubscribeHandler{broker, upgrader}
+	return handler
+}
+
+func (h *SubscribeHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
+	h.upgrader.Upgrade(ctx, h.serve)
+}
+
+// serve reads requests until the connection fails, all writes happen in writeLoop
+func (h *SubscribeHandler) serve(conn *websocket.Conn) {
+	subscription := h.broker.Subscribe()
+	defer subscription.Close()
+	replies := make(chan interface{}, 1)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		writeLoop(conn, subscription, replies, done)
+	}()
+	reply := func(response interface{}) {
+		select {
+		case replies <- response:
+		case <-stopped:
+		}
+	}
+
+	conn.SetReadLimit(MaxWebsocketMessageSize)
+	conn.SetReadDeadline(time.Now().Add(websocketPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(websocketPongTimeout))
+	})
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		var request subscribeRequest
+		err = json.Unmarshal(message, &request)
+		if err != nil {
+			reply(router.ErrorResponse{Error: true, Code: "invalid_request", Reason: "invalid request"})
+			continue
+		}
+		reply(applySubscribeRequest(subscription, request))
+	}
+	close(done)
+	<-stopped
+}
+
+func applySubscribeRequest(subscription *events.Subscription, request subscribeRequest) interface{} {
+	topic := request.Topic
+	if request.Address != "" {
+		addressBytes := common.FromHex(request.Address)
+		if len(addressBytes) != transaction.AddressLength {
+			return router.ErrorResponse{Error: true, Code: "invalid_request", Reason: "invalid address"}
+		}
+		topic = events.AddressTopic(common.BytesToAddress(addressBytes))
+	} else if topic != events.BlocksTopic {
+		return router.ErrorResponse{Error: true, Code: "invalid_request", Reason: "unknown topic"}
+	}
+	switch request.Action {
+	case "subscribe":
+		err := subscription.Watch(topic)
+		if err == events.ErrTooManyTopics {
+			return router.ErrorResponse{Error: true, Code: "too_many_topics", Reason: "too many subscriptions on one connection"}
+		}
+	case "unsubscribe":
+		subscription.Unwatch(topic)
+	default:
+		return router.ErrorResponse{Error: true, Code: "invalid_request", Reason: "unknown action"}
+	}
+	return subscribeResponse{false, request.Action, topic}
+}
+
+func writeLoop(conn *websocket.Conn, subscription *events.Subscription, replies <-chan interface{}, done <-chan struct{}) {
+	ticker := time.NewTicker(websocketPingPeriod)
+	defer ticker.Stop()
+	// unblocks the reader if writing fails
+	defer conn.Close()
+	for {
+		var err error
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok {
+				message := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscriber is too slow")
+				conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(websocketWriteTimeout))
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+			err = conn.WriteJSON(event)
+		case reply := <-replies:
+			conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+			err = conn.WriteJSON(reply)
+		case <-ticker.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketWriteTimeout))
+		case <-done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -3,10 +3,9 @@ package handlers
 import (
 	"encoding/json"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
 	common "github.com/ethereum/go-ethereum/common"
 	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
-	"github.com/matterinc/PlasmaCommons/types"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 	"github.com/valyala/fasthttp"
 )
 
@@ -23,11 +22,11 @@ type createUTXOResponse struct {
 }
 
 type CreateUTXOHandler struct {
-	db          *fdb.Database
+	db          storage.Database
 	utxoCreator *foundationdb.TestUTXOcreator
 }
 
-func NewCreateUTXOHandler(db *fdb.Database) *CreateUTXOHandler {
+func NewCreateUTXOHandler(db storage.Database) *CreateUTXOHandler {
 	creator := foundationdb.NewTestUTXOcreator(db)
 	handler := &CreateUTXOHandler{db, creator}
 	return handler
@@ -37,21 +36,24 @@ func (h *CreateUTXOHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
 	var requestJSON createUTXOrequest
 	err := json.Unmarshal(ctx.PostBody(), &requestJSON)
 	if err != nil {
-		writeFasthttpErrorResponse(ctx)
+		writeInvalidRequest(ctx, "invalid request")
 		return
 	}
 
 	forBytes := common.FromHex(requestJSON.For)
 	address := common.Address{}
 	copy(address[:], forBytes)
-	bigint := types.NewBigInt(0)
-	bigint.SetString(requestJSON.Value, 10)
+	bigint, ok := parseUnsignedBigInt(requestJSON.Value)
+	if !ok {
+		writeInvalidRequest(ctx, "invalid value")
+		return
+	}
 	blockNumber := uint32(requestJSON.BlockNumber)
 	transactionNumber := uint32(requestJSON.TransactionNumber)
 	outputNumber := uint8(requestJSON.OutputNumber)
 	err = h.utxoCreator.InsertUTXO(address, blockNumber, transactionNumber, outputNumber, bigint)
 	if err != nil {
-		writeFasthttpErrorResponse(ctx)
+		writeStorageUnavailable(ctx, "failed to create UTXO")
 		return
 	}
 	writeFasthttpSuccessResponse(ctx)
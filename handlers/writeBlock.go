@@ -1,40 +1,80 @@
 package handlers
 
 import (
+	"encoding/binary"
+
+	"github.com/matterinc/PlasmaBlockCreator/events"
+	"github.com/matterinc/PlasmaBlockCreator/router"
 	"github.com/matterinc/PlasmaCommons/block"
+	"github.com/matterinc/PlasmaCommons/transaction"
 	"github.com/valyala/fasthttp"
 
-	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
 	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
 )
 
 type WriteBlockHandler struct {
-	db     *fdb.Database
-	writer *foundationdb.BlockWriter
+	db        storage.Database
+	writer    *foundationdb.BlockWriter
+	publisher events.Publisher
 }
 
-func NewWriteBlockHandler(db *fdb.Database) *WriteBlockHandler {
+func NewWriteBlockHandler(db storage.Database, publisher events.Publisher) *WriteBlockHandler {
 	writer := foundationdb.NewBlockWriter(db)
-	handler := &WriteBlockHandler{db, writer}
+	handler := &WriteBlockHandler{db, writer, publisher}
 	return handler
 }
 
 func (h *WriteBlockHandler) HandlerFunc(ctx *fasthttp.RequestCtx) {
 	rawBlock := ctx.PostBody()
 	if len(rawBlock) == 0 {
-		writeGeneralErrorResponse(ctx)
+		writeInvalidRequest(ctx, "empty block")
 		return
 	}
 	block, err := block.NewBlockFromBytes(rawBlock)
 	if err != nil {
-		writeGeneralErrorResponse(ctx)
+		writeInvalidRequest(ctx, "invalid block")
 		return
 	}
-	err = h.writer.WriteBlock(*block)
+	err = h.write(block)
 	if err != nil {
-		writeGeneralErrorResponse(ctx)
+		router.WriteError(ctx, fasthttp.StatusConflict, "write_failed", err.Error())
 		return
 	}
 	writeFasthttpSuccessResponse(ctx)
 	return
 }
+
+// write applies a block to the UTXO set, archives it and publishes its events
+func (h *WriteBlockHandler) write(newBlock *block.Block) error {
+	err := h.writer.WriteBlock(*newBlock)
+	if err != nil {
+		return err
+	}
+	h.publishBlock(newBlock)
+	return nil
+}
+
+// publishBlock notifies owners of the new outputs and then block subscribers, a block
+// that is written again is published again
+func (h *WriteBlockHandler) publishBlock(writtenBlock *block.Block) {
+	blockNumber := binary.BigEndian.Uint32(writtenBlock.BlockHeader.BlockNumber[:])
+	for i, tx := range writtenBlock.Transactions {
+		hash, _, err := foundationdb.TransactionHash(tx)
+		if err != nil {
+			return
+		}
+		for j := range tx.UnsignedTransaction.Outputs {
+			index, err := transaction.CreateUTXOIndexForOutput(tx, blockNumber, uint32(i), j)
+			if err != nil {
+				return
+			}
+			h.publisher.Publish(events.NewUTXOEvent(events.UTXOCreated, index, hash))
+		}
+	}
+	blockHash, err := writtenBlock.BlockHeader.GetHash()
+	if err != nil {
+		return
+	}
+	h.publisher.Publish(events.NewBlockWrittenEvent(blockNumber, uint32(len(writtenBlock.Transactions)), blockHash[:]))
+}
@@ -1,45 +1,126 @@
 package handlers
 
 import (
-	"encoding/json"
+	"strconv"
+	"time"
 
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaBlockCreator/router"
 	"github.com/valyala/fasthttp"
 )
 
-type generalErrorResponse struct {
-	Error  bool   `json:"error"`
-	Reason string `json:"reason,omitempty"`
+// MaxJSONBodySize is enough for a request to any route that takes a JSON body
+const MaxJSONBodySize = 4096
+
+// apiError is a stable failure class. Clients should switch on the code,
+// the message is for humans only and may change
+type apiError struct {
+	Code       string
+	Message    string
+	StatusCode int
+	Retryable  bool
+	// RetryAfter is sent as the Retry-After header when set
+	RetryAfter time.Duration
+}
+
+// withMessage keeps the code of a failure class but gives a more specific message
+func (e apiError) withMessage(message string) apiError {
+	e.Message = message
+	return e
+}
+
+func invalidRequest(reason string) *apiError {
+	return &apiError{"invalid_request", reason, fasthttp.StatusBadRequest, false, 0}
+}
+
+func notFound(reason string) *apiError {
+	return &apiError{"not_found", reason, fasthttp.StatusNotFound, false, 0}
+}
+
+func storageUnavailable(reason string) *apiError {
+	return &apiError{"storage_unavailable", reason, fasthttp.StatusServiceUnavailable, true, 0}
+}
+
+// retryAfterSeconds rounds up, so a client that waits as told is not limited again
+func (e *apiError) retryAfterSeconds() int {
+	return int((e.RetryAfter + time.Second - 1) / time.Second)
 }
 
-func writeGeneralErrorResponse(ctx *fasthttp.RequestCtx) {
-	ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
-	ctx.Response.Header.Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-	ctx.Response.Header.Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-	ctx.SetContentType("application/json")
-	ctx.SetStatusCode(fasthttp.StatusOK)
-	response := generalErrorResponse{Error: true}
-	body, _ := json.Marshal(response)
-	ctx.SetBody(body)
+func setRetryAfter(ctx *fasthttp.RequestCtx, e *apiError) {
+	if e.RetryAfter > 0 {
+		ctx.Response.Header.Set("Retry-After", strconv.Itoa(e.retryAfterSeconds()))
+	}
 }
 
-func writeFasthttpErrorResponse(ctx *fasthttp.RequestCtx) {
-	ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
-	ctx.Response.Header.Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-	ctx.Response.Header.Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-	ctx.SetContentType("application/json")
-	ctx.SetStatusCode(fasthttp.StatusOK)
-	response := sendRawRLPTXResponse{Error: true, Reason: "invalid transaction"}
-	body, _ := json.Marshal(response)
-	ctx.SetBody(body)
+func writeAPIError(ctx *fasthttp.RequestCtx, e *apiError) {
+	router.WriteError(ctx, e.StatusCode, e.Code, e.Message)
+	setRetryAfter(ctx, e)
+}
+
+func writeInvalidRequest(ctx *fasthttp.RequestCtx, reason string) {
+	writeAPIError(ctx, invalidRequest(reason))
+}
+
+func writeNotFound(ctx *fasthttp.RequestCtx, reason string) {
+	writeAPIError(ctx, notFound(reason))
+}
+
+func writeStorageUnavailable(ctx *fasthttp.RequestCtx, reason string) {
+	writeAPIError(ctx, storageUnavailable(reason))
 }
 
 func writeFasthttpSuccessResponse(ctx *fasthttp.RequestCtx) {
-	ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
-	ctx.Response.Header.Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-	ctx.Response.Header.Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-	ctx.SetContentType("application/json")
-	ctx.SetStatusCode(fasthttp.StatusOK)
 	response := sendRawRLPTXResponse{Error: false, Accepted: true}
-	body, _ := json.Marshal(response)
-	ctx.SetBody(body)
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
+}
+
+func writeSendRawTXSuccessResponse(ctx *fasthttp.RequestCtx, hash []byte, counter uint64) {
+	response := sendRawRLPTXResponse{Error: false, Accepted: true, Hash: common.ToHex(hash), Counter: counter}
+	router.WriteJSON(ctx, fasthttp.StatusOK, response)
+}
+
+// writeSendRawTXCBORResponse has the same fields and status codes as the JSON response,
+// the hash is a byte string
+func writeSendRawTXCBORResponse(ctx *fasthttp.RequestCtx, hash []byte, counter uint64, failure *apiError) {
+	w := &cborWriter{}
+	if failure != nil {
+		entries := 4
+		if failure.RetryAfter > 0 {
+			entries++
+		}
+		w.mapHeader(entries)
+		w.text("error")
+		w.bool(true)
+		w.text("code")
+		w.text(failure.Code)
+		w.text("reason")
+		w.text(failure.Message)
+		w.text("retryable")
+		w.bool(failure.Retryable)
+		if failure.RetryAfter > 0 {
+			w.text("retryAfter")
+			w.uint(uint64(failure.retryAfterSeconds()))
+		}
+		setRetryAfter(ctx, failure)
+		ctx.SetStatusCode(failure.StatusCode)
+	} else {
+		entries := 3
+		if counter != 0 {
+			entries++
+		}
+		w.mapHeader(entries)
+		w.text("error")
+		w.bool(false)
+		w.text("accepted")
+		w.bool(true)
+		w.text("hash")
+		w.bytes(hash)
+		if counter != 0 {
+			w.text("counter")
+			w.uint(counter)
+		}
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	}
+	ctx.SetContentType(CBORContentType)
+	ctx.SetBody(w.buf)
 }
@@ -1,4 +1,3 @@
 #!/bin/bash
 fdbcli --exec "status details"
-cd redisPrep && npm install && node prepareRedis.js && cd ..
 go run -v server.go
\ No newline at end of file
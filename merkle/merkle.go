@@ -0,0 +1,55 @@
+package merkle
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/matterinc/PlasmaCommons/merkleTree"
+	"github.com/matterinc/PlasmaCommons/transaction"
+)
+
+// Tree builds the tree of block transactions with PlasmaCommons, the same way
+// block.NewBlock does for the root it signs
+func Tree(txs []*transaction.SignedTransaction) (*merkleTree.MerkleTree, error) {
+	if len(txs) == 0 {
+		return nil, errors.New("Tree has no leaves")
+	}
+	contents := make([]merkleTree.Content, len(txs))
+	for i, tx := range txs {
+		contents[i] = tx
+	}
+	return merkleTree.NewTree(contents)
+}
+
+// Proof returns the sibling hashes from the leaf at index up to the root of the tree
+func Proof(tree *merkleTree.MerkleTree, index int) ([][]byte, error) {
+	if index < 0 || index >= len(tree.Leafs) {
+		return nil, errors.New("Leaf index is out of range")
+	}
+	branch := [][]byte{}
+	current := tree.Leafs[index]
+	for current.Parent != nil {
+		parent := current.Parent
+		if parent.Left == current {
+			branch = append(branch, parent.Right.Hash)
+		} else {
+			branch = append(branch, parent.Left.Hash)
+		}
+		current = parent
+	}
+	return branch, nil
+}
+
+// Verify recomputes the root from a leaf and its branch the way the contract does
+func Verify(leaf []byte, index int, branch [][]byte, root []byte) bool {
+	current := leaf
+	for _, sibling := range branch {
+		if index%2 == 0 {
+			current = crypto.Keccak256(current, sibling)
+		} else {
+			current = crypto.Keccak256(sibling, current)
+		}
+		index = index / 2
+	}
+	return string(current) == string(root)
+}
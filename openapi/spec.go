@@ -0,0 +1,190 @@
+package openapi
+
+import (
+	"errors"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// Spec is the part of an OpenAPI 3 document that is needed to check requests
+type Spec struct {
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type PathItem struct {
+	Get    *Operation `yaml:"get"`
+	Post   *Operation `yaml:"post"`
+	Put    *Operation `yaml:"put"`
+	Delete *Operation `yaml:"delete"`
+}
+
+type Operation struct {
+	RequestBody *RequestBody `yaml:"requestBody"`
+}
+
+type RequestBody struct {
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Schema supports the keywords used in docs/plasma.yaml, others are ignored
+type Schema struct {
+	Ref        string             `yaml:"$ref"`
+	Type       string             `yaml:"type"`
+	Properties map[string]*Schema `yaml:"properties"`
+	Required   []string           `yaml:"required"`
+	Items      *Schema            `yaml:"items"`
+	OneOf      []*Schema          `yaml:"oneOf"`
+	Enum       []interface{}      `yaml:"enum"`
+	Pattern    string             `yaml:"pattern"`
+	MinLength  *int               `yaml:"minLength"`
+	MaxLength  *int               `yaml:"maxLength"`
+	Minimum    *float64           `yaml:"minimum"`
+	Maximum    *float64           `yaml:"maximum"`
+	MinItems   *int               `yaml:"minItems"`
+	MaxItems   *int               `yaml:"maxItems"`
+
+	pattern *regexp.Regexp
+}
+
+// Load reads a spec from a YAML file
+func Load(path string) (*Spec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse checks that every reference can be resolved and every pattern compiles,
+// so that a broken spec fails on startup instead of on requests
+func Parse(data []byte) (*Spec, error) {
+	spec := &Spec{}
+	err := yaml.Unmarshal(data, spec)
+	if err != nil {
+		return nil, err
+	}
+	prepared := make(map[*Schema]bool)
+	for _, schema := range spec.Components.Schemas {
+		err = spec.prepare(schema, prepared)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for path, item := range spec.Paths {
+		if item == nil {
+			return nil, errors.New("Empty path " + path)
+		}
+		for _, operation := range item.operations() {
+			if operation.RequestBody == nil {
+				continue
+			}
+			for _, media := range operation.RequestBody.Content {
+				if media == nil {
+					continue
+				}
+				err = spec.prepare(media.Schema, prepared)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return spec, nil
+}
+
+func (spec *Spec) prepare(schema *Schema, prepared map[*Schema]bool) error {
+	if schema == nil || prepared[schema] {
+		return nil
+	}
+	prepared[schema] = true
+	if schema.Ref != "" {
+		if spec.resolve(schema) == nil {
+			return errors.New("Unknown schema " + schema.Ref)
+		}
+		return nil
+	}
+	if schema.Pattern != "" {
+		pattern, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			return err
+		}
+		schema.pattern = pattern
+	}
+	for _, property := range schema.Properties {
+		err := spec.prepare(property, prepared)
+		if err != nil {
+			return err
+		}
+	}
+	for _, option := range schema.OneOf {
+		err := spec.prepare(option, prepared)
+		if err != nil {
+			return err
+		}
+	}
+	return spec.prepare(schema.Items, prepared)
+}
+
+// resolve follows a reference to a component schema, only local references are supported
+func (spec *Spec) resolve(schema *Schema) *Schema {
+	for hops := 0; schema != nil && schema.Ref != ""; hops++ {
+		if !strings.HasPrefix(schema.Ref, schemaRefPrefix) || hops > len(spec.Components.Schemas) {
+			return nil
+		}
+		schema = spec.Components.Schemas[strings.TrimPrefix(schema.Ref, schemaRefPrefix)]
+	}
+	return schema
+}
+
+// Routes lists documented operations in the same order as router.Router does
+func (spec *Spec) Routes() []router.Route {
+	routes := []router.Route{}
+	for path, item := range spec.Paths {
+		for method := range item.operations() {
+			routes = append(routes, router.Route{Method: method, Path: path})
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+func (spec *Spec) operation(method string, path string) *Operation {
+	item, ok := spec.Paths[path]
+	if !ok {
+		return nil
+	}
+	return item.operations()[method]
+}
+
+func (item *PathItem) operations() map[string]*Operation {
+	operations := make(map[string]*Operation)
+	if item == nil {
+		return operations
+	}
+	for method, operation := range map[string]*Operation{"GET": item.Get, "POST": item.Post, "PUT": item.Put, "DELETE": item.Delete} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+	return operations
+}
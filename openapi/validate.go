@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/valyala/fasthttp"
+)
+
+// bodyField names the request body itself in field errors
+const bodyField = "body"
+
+// FieldError tells which field of a request body is wrong, nested fields are joined
+// with dots and array items are given by their index
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+type validationErrorResponse struct {
+	router.ErrorResponse
+	Fields []FieldError `json:"fields"`
+}
+
+// Validate rejects requests with a JSON body that does not match the schema of their
+// operation. Operations without a request body, routes missing in the spec and bodies
+// of other media types are passed as they are
+func Validate(spec *Spec) router.Middleware {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			fields := spec.ValidateRequest(string(ctx.Method()), string(ctx.Path()), string(ctx.Request.Header.ContentType()), ctx.PostBody())
+			if len(fields) != 0 {
+				response := validationErrorResponse{router.ErrorResponse{Error: true,
+					Code:   "invalid_request",
+					Reason: fields[0].Field + " " + fields[0].Reason},
+					fields}
+				router.WriteJSON(ctx, fasthttp.StatusBadRequest, response)
+				return
+			}
+			next(ctx)
+		}
+	}
+}
+
+// ValidateRequest returns every mismatch of a body against the spec. A body without a
+// known content type is checked as JSON, handlers never looked at the header
+func (spec *Spec) ValidateRequest(method string, path string, contentType string, body []byte) []FieldError {
+	operation := spec.operation(method, path)
+	if operation == nil || operation.RequestBody == nil {
+		return nil
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	media, ok := operation.RequestBody.Content[mediaType]
+	if !ok {
+		mediaType = "application/json"
+		media, ok = operation.RequestBody.Content[mediaType]
+	}
+	if !ok || media == nil || media.Schema == nil || !strings.HasSuffix(mediaType, "json") {
+		return nil
+	}
+	if len(body) == 0 {
+		if operation.RequestBody.Required {
+			return []FieldError{{bodyField, "is required"}}
+		}
+		return nil
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	err := decoder.Decode(&value)
+	if err != nil || decoder.More() {
+		return []FieldError{{bodyField, "is not valid JSON"}}
+	}
+	return spec.validate(media.Schema, value, bodyField, nil)
+}
+
+func (spec *Spec) validate(schema *Schema, value interface{}, field string, fields []FieldError) []FieldError {
+	schema = spec.resolve(schema)
+	if schema == nil {
+		return fields
+	}
+	if len(schema.OneOf) != 0 {
+		matched := 0
+		for _, option := range schema.OneOf {
+			if len(spec.validate(option, value, field, nil)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return append(fields, FieldError{field, "does not match exactly one of the allowed schemas"})
+		}
+	}
+	if len(schema.Enum) != 0 && !inEnum(schema.Enum, value) {
+		options := make([]string, len(schema.Enum))
+		for i, option := range schema.Enum {
+			options[i] = fmt.Sprint(option)
+		}
+		return append(fields, FieldError{field, "should be one of " + strings.Join(options, ", ")})
+	}
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return append(fields, FieldError{field, "should be an object"})
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				fields = append(fields, FieldError{join(field, name), "is required"})
+			}
+		}
+		// sorted, so the first error of a response does not change between requests
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if propertyValue, ok := object[name]; ok {
+				fields = spec.validate(schema.Properties[name], propertyValue, join(field, name), fields)
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return append(fields, FieldError{field, "should be an array"})
+		}
+		if schema.MinItems != nil && len(array) < *schema.MinItems {
+			fields = append(fields, FieldError{field, "should have at least " + strconv.Itoa(*schema.MinItems) + " items"})
+		}
+		if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+			return append(fields, FieldError{field, "should have at most " + strconv.Itoa(*schema.MaxItems) + " items"})
+		}
+		for i, item := range array {
+			fields = spec.validate(schema.Items, item, field+"["+strconv.Itoa(i)+"]", fields)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return append(fields, FieldError{field, "should be a string"})
+		}
+		if schema.MinLength != nil && len(s) < *schema.MinLength {
+			fields = append(fields, FieldError{field, "should be at least " + strconv.Itoa(*schema.MinLength) + " characters long"})
+		}
+		if schema.MaxLength != nil && len(s) > *schema.MaxLength {
+			fields = append(fields, FieldError{field, "should be at most " + strconv.Itoa(*schema.MaxLength) + " characters long"})
+		}
+		if schema.pattern != nil && !schema.pattern.MatchString(s) {
+			fields = append(fields, FieldError{field, "should match " + schema.Pattern})
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if schema.Type == "integer" {
+			// 1.0 or 1e3 are numbers but not integers
+			ok = ok && !strings.ContainsAny(number.String(), ".eE")
+			if !ok {
+				return append(fields, FieldError{field, "should be an integer"})
+			}
+		}
+		if !ok {
+			return append(fields, FieldError{field, "should be a number"})
+		}
+		f, err := number.Float64()
+		if err != nil {
+			return append(fields, FieldError{field, "should be a number"})
+		}
+		if schema.Minimum != nil && f < *schema.Minimum {
+			fields = append(fields, FieldError{field, "should be at least " + formatNumber(*schema.Minimum)})
+		}
+		if schema.Maximum != nil && f > *schema.Maximum {
+			fields = append(fields, FieldError{field, "should be at most " + formatNumber(*schema.Maximum)})
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return append(fields, FieldError{field, "should be a boolean"})
+		}
+	}
+	return fields
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}, nil:
+		return false
+	}
+	for _, option := range enum {
+		if fmt.Sprint(option) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// join names a property of the body without the body prefix
+func join(field string, name string) string {
+	if field == bodyField {
+		return name
+	}
+	return field + "." + name
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+const testSpec = `
+paths:
+  /items:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/octet-stream:
+            schema:
+              type: string
+          application/json:
+            schema:
+              $ref: '#/components/schemas/items'
+components:
+  schemas:
+    items:
+      type: object
+      properties:
+        number:
+          type: string
+          pattern: '^[0-9]+$'
+        limit:
+          type: integer
+          minimum: 1
+          maximum: 100
+        state:
+          type: string
+          enum: [open, closed]
+        list:
+          type: array
+          items:
+            type: integer
+      required:
+        - number
+`
+
+func TestValidateReportsFields(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := spec.ValidateRequest("POST", "/items", "application/json", []byte(`{"limit": 1.5, "state": "gone", "list": [1, "2"]}`))
+	expected := []FieldError{{"number", "is required"},
+		{"limit", "should be an integer"},
+		{"list[1]", "should be an integer"},
+		{"state", "should be one of open, closed"}}
+	if len(fields) != len(expected) {
+		t.Fatal("Unexpected errors", fields)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Fatal("Unexpected error", fields[i])
+		}
+	}
+	if len(spec.ValidateRequest("POST", "/items", "", []byte(`{"number": "12", "limit": 100}`))) != 0 {
+		t.Fatal("Valid body should pass")
+	}
+	if len(spec.ValidateRequest("POST", "/items", "application/octet-stream", []byte{0xff})) != 0 {
+		t.Fatal("Bodies of other media types should pass")
+	}
+	fields = spec.ValidateRequest("POST", "/items", "application/json", []byte(`{"number": "-1", "limit": 101}`))
+	if len(fields) != 2 || fields[0].Field != "limit" || fields[1].Field != "number" {
+		t.Fatal("Unexpected errors", fields)
+	}
+}
+
+func TestValidateMiddleware(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	called := false
+	handler := Validate(spec)(func(ctx *fasthttp.RequestCtx) {
+		called = true
+	})
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod("POST")
+	ctx.Request.SetRequestURI("/items")
+	handler(ctx)
+	var response validationErrorResponse
+	json.Unmarshal(ctx.Response.Body(), &response)
+	if called || ctx.Response.StatusCode() != fasthttp.StatusBadRequest || response.Code != "invalid_request" ||
+		len(response.Fields) != 1 || response.Fields[0].Field != "body" {
+		t.Fatal("Missing body should be rejected: " + string(ctx.Response.Body()))
+	}
+	_, err = Parse([]byte("components:\n  schemas:\n    a:\n      $ref: '#/components/schemas/b'\n"))
+	if err == nil {
+		t.Fatal("Unknown reference should fail to parse")
+	}
+}
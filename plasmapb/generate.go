@@ -0,0 +1,4 @@
+// Package plasmapb contains protobuf messages and the gRPC service of the operator
+package plasmapb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative plasma.proto
@@ -0,0 +1,335 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: plasma.proto
+
+package plasmapb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Plasma_SendRawTransaction_FullMethodName  = "/plasma.Plasma/SendRawTransaction"
+	Plasma_SendRawTransactions_FullMethodName = "/plasma.Plasma/SendRawTransactions"
+	Plasma_ListUTXOs_FullMethodName           = "/plasma.Plasma/ListUTXOs"
+	Plasma_GetLastWrittenBlock_FullMethodName = "/plasma.Plasma/GetLastWrittenBlock"
+	Plasma_GetBlockHeader_FullMethodName      = "/plasma.Plasma/GetBlockHeader"
+	Plasma_GetBlock_FullMethodName            = "/plasma.Plasma/GetBlock"
+)
+
+// PlasmaClient is the client API for Plasma service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PlasmaClient interface {
+	// SendRawTransaction accepts a signed RLP encoded transaction
+	SendRawTransaction(ctx context.Context, in *RawTransaction, opts ...grpc.CallOption) (*SendResult, error)
+	// SendRawTransactions accepts a stream of transactions and reports every one of them
+	// when the client closes the stream
+	SendRawTransactions(ctx context.Context, opts ...grpc.CallOption) (Plasma_SendRawTransactionsClient, error)
+	ListUTXOs(ctx context.Context, in *ListUTXOsRequest, opts ...grpc.CallOption) (*ListUTXOsResponse, error)
+	GetLastWrittenBlock(ctx context.Context, in *LastWrittenBlockRequest, opts ...grpc.CallOption) (*LastWrittenBlock, error)
+	GetBlockHeader(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*BlockHeader, error)
+	GetBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Block, error)
+}
+
+type plasmaClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlasmaClient(cc grpc.ClientConnInterface) PlasmaClient {
+	return &plasmaClient{cc}
+}
+
+func (c *plasmaClient) SendRawTransaction(ctx context.Context, in *RawTransaction, opts ...grpc.CallOption) (*SendResult, error) {
+	out := new(SendResult)
+	err := c.cc.Invoke(ctx, Plasma_SendRawTransaction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plasmaClient) SendRawTransactions(ctx context.Context, opts ...grpc.CallOption) (Plasma_SendRawTransactionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Plasma_ServiceDesc.Streams[0], Plasma_SendRawTransactions_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &plasmaSendRawTransactionsClient{stream}
+	return x, nil
+}
+
+type Plasma_SendRawTransactionsClient interface {
+	Send(*RawTransaction) error
+	CloseAndRecv() (*SendSummary, error)
+	grpc.ClientStream
+}
+
+type plasmaSendRawTransactionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *plasmaSendRawTransactionsClient) Send(m *RawTransaction) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *plasmaSendRawTransactionsClient) CloseAndRecv() (*SendSummary, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SendSummary)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *plasmaClient) ListUTXOs(ctx context.Context, in *ListUTXOsRequest, opts ...grpc.CallOption) (*ListUTXOsResponse, error) {
+	out := new(ListUTXOsResponse)
+	err := c.cc.Invoke(ctx, Plasma_ListUTXOs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plasmaClient) GetLastWrittenBlock(ctx context.Context, in *LastWrittenBlockRequest, opts ...grpc.CallOption) (*LastWrittenBlock, error) {
+	out := new(LastWrittenBlock)
+	err := c.cc.Invoke(ctx, Plasma_GetLastWrittenBlock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plasmaClient) GetBlockHeader(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*BlockHeader, error) {
+	out := new(BlockHeader)
+	err := c.cc.Invoke(ctx, Plasma_GetBlockHeader_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plasmaClient) GetBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, Plasma_GetBlock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PlasmaServer is the server API for Plasma service.
+// All implementations must embed UnimplementedPlasmaServer
+// for forward compatibility
+type PlasmaServer interface {
+	// SendRawTransaction accepts a signed RLP encoded transaction
+	SendRawTransaction(context.Context, *RawTransaction) (*SendResult, error)
+	// SendRawTransactions accepts a stream of transactions and reports every one of them
+	// when the client closes the stream
+	SendRawTransactions(Plasma_SendRawTransactionsServer) error
+	ListUTXOs(context.Context, *ListUTXOsRequest) (*ListUTXOsResponse, error)
+	GetLastWrittenBlock(context.Context, *LastWrittenBlockRequest) (*LastWrittenBlock, error)
+	GetBlockHeader(context.Context, *BlockRequest) (*BlockHeader, error)
+	GetBlock(context.Context, *BlockRequest) (*Block, error)
+	mustEmbedUnimplementedPlasmaServer()
+}
+
+// UnimplementedPlasmaServer must be embedded to have forward compatible implementations.
+type UnimplementedPlasmaServer struct {
+}
+
+func (UnimplementedPlasmaServer) SendRawTransaction(context.Context, *RawTransaction) (*SendResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendRawTransaction not implemented")
+}
+func (UnimplementedPlasmaServer) SendRawTransactions(Plasma_SendRawTransactionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendRawTransactions not implemented")
+}
+func (UnimplementedPlasmaServer) ListUTXOs(context.Context, *ListUTXOsRequest) (*ListUTXOsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUTXOs not implemented")
+}
+func (UnimplementedPlasmaServer) GetLastWrittenBlock(context.Context, *LastWrittenBlockRequest) (*LastWrittenBlock, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLastWrittenBlock not implemented")
+}
+func (UnimplementedPlasmaServer) GetBlockHeader(context.Context, *BlockRequest) (*BlockHeader, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockHeader not implemented")
+}
+func (UnimplementedPlasmaServer) GetBlock(context.Context, *BlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (UnimplementedPlasmaServer) mustEmbedUnimplementedPlasmaServer() {}
+
+// UnsafePlasmaServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PlasmaServer will
+// result in compilation errors.
+type UnsafePlasmaServer interface {
+	mustEmbedUnimplementedPlasmaServer()
+}
+
+func RegisterPlasmaServer(s grpc.ServiceRegistrar, srv PlasmaServer) {
+	s.RegisterService(&Plasma_ServiceDesc, srv)
+}
+
+func _Plasma_SendRawTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RawTransaction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlasmaServer).SendRawTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Plasma_SendRawTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlasmaServer).SendRawTransaction(ctx, req.(*RawTransaction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plasma_SendRawTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PlasmaServer).SendRawTransactions(&plasmaSendRawTransactionsServer{stream})
+}
+
+type Plasma_SendRawTransactionsServer interface {
+	SendAndClose(*SendSummary) error
+	Recv() (*RawTransaction, error)
+	grpc.ServerStream
+}
+
+type plasmaSendRawTransactionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *plasmaSendRawTransactionsServer) SendAndClose(m *SendSummary) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *plasmaSendRawTransactionsServer) Recv() (*RawTransaction, error) {
+	m := new(RawTransaction)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Plasma_ListUTXOs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUTXOsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlasmaServer).ListUTXOs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Plasma_ListUTXOs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlasmaServer).ListUTXOs(ctx, req.(*ListUTXOsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plasma_GetLastWrittenBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LastWrittenBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlasmaServer).GetLastWrittenBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Plasma_GetLastWrittenBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlasmaServer).GetLastWrittenBlock(ctx, req.(*LastWrittenBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plasma_GetBlockHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlasmaServer).GetBlockHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Plasma_GetBlockHeader_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlasmaServer).GetBlockHeader(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plasma_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlasmaServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Plasma_GetBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlasmaServer).GetBlock(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Plasma_ServiceDesc is the grpc.ServiceDesc for Plasma service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Plasma_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plasma.Plasma",
+	HandlerType: (*PlasmaServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendRawTransaction",
+			Handler:    _Plasma_SendRawTransaction_Handler,
+		},
+		{
+			MethodName: "ListUTXOs",
+			Handler:    _Plasma_ListUTXOs_Handler,
+		},
+		{
+			MethodName: "GetLastWrittenBlock",
+			Handler:    _Plasma_GetLastWrittenBlock_Handler,
+		},
+		{
+			MethodName: "GetBlockHeader",
+			Handler:    _Plasma_GetBlockHeader_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _Plasma_GetBlock_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SendRawTransactions",
+			Handler:       _Plasma_SendRawTransactions_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "plasma.proto",
+}
@@ -0,0 +1,37 @@
+package ratelimit
+
+// Concurrency limits the calls served at the same time, servers of one binary share it so
+// a second protocol does not double the load
+type Concurrency struct {
+	slots chan struct{}
+}
+
+// NewConcurrency returns nil if limit is not positive, a nil limit allows everything
+func NewConcurrency(limit int) *Concurrency {
+	if limit <= 0 {
+		return nil
+	}
+	concurrency := &Concurrency{slots: make(chan struct{}, limit)}
+	return concurrency
+}
+
+// Acquire does not wait, it returns false if all slots are taken
+func (c *Concurrency) Acquire() bool {
+	if c == nil {
+		return true
+	}
+	select {
+	case c.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release frees a slot taken by a successful Acquire
+func (c *Concurrency) Release() {
+	if c == nil {
+		return
+	}
+	<-c.slots
+}
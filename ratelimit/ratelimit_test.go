@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterRefillsBuckets(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := NewLimiter(2, 3)
+	limiter.now = func() time.Time { return now }
+	limiter.lastSweep = now
+	for i := 0; i < 3; i++ {
+		ok, _ := limiter.Take("a")
+		if !ok {
+			t.Fatal("Burst should be allowed")
+		}
+	}
+	ok, retryAfter := limiter.Take("a")
+	if ok || retryAfter != 500*time.Millisecond {
+		t.Fatal("Empty bucket should tell when the next token comes", retryAfter)
+	}
+	if ok, _ := limiter.Take("b"); !ok {
+		t.Fatal("Keys should have their own buckets")
+	}
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := limiter.Take("a"); !ok {
+		t.Fatal("Bucket should be refilled")
+	}
+	now = now.Add(2 * sweepInterval)
+	limiter.Take("c")
+	if len(limiter.buckets) != 1 {
+		t.Fatal("Full buckets should be dropped")
+	}
+	var disabled *Limiter = NewLimiter(0, 10)
+	if ok, _ := disabled.Take("a"); !ok {
+		t.Fatal("Disabled limiter should allow everything")
+	}
+}
+
+func TestConcurrencyIsShared(t *testing.T) {
+	concurrency := NewConcurrency(2)
+	if !concurrency.Acquire() || !concurrency.Acquire() {
+		t.Fatal("Slots should be acquired up to the limit")
+	}
+	if concurrency.Acquire() {
+		t.Fatal("No slot should be left")
+	}
+	concurrency.Release()
+	if !concurrency.Acquire() {
+		t.Fatal("Released slot should be acquired again")
+	}
+	var unlimited *Concurrency
+	if !unlimited.Acquire() {
+		t.Fatal("Nil limit should allow everything")
+	}
+	unlimited.Release()
+}
@@ -0,0 +1,102 @@
+package rejections
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+
+	redis "github.com/go-redis/redis"
+)
+
+// Store remembers for a while why a transaction was rejected. Anyone can have a transaction
+// rejected, so entries expire and are never written to the database
+type Store interface {
+	// Reject replaces the reason of an earlier rejection
+	Reject(hash []byte, reason string) error
+	// Reason returns false if the transaction was not rejected recently
+	Reason(hash []byte) (string, bool, error)
+}
+
+// MemoryStore keeps at most capacity rejections of the process, the oldest are dropped first
+type MemoryStore struct {
+	ttl      time.Duration
+	capacity int
+	mutex    sync.Mutex
+	entries  map[string]*entry
+	// order holds the keys from the oldest rejection to the latest one
+	order []string
+	now   func() time.Time
+}
+
+type entry struct {
+	reason  string
+	expires time.Time
+}
+
+func NewMemoryStore(ttl time.Duration, capacity int) *MemoryStore {
+	if capacity < 1 {
+		capacity = 1
+	}
+	store := &MemoryStore{ttl: ttl, capacity: capacity, entries: make(map[string]*entry), now: time.Now}
+	return store
+}
+
+func (s *MemoryStore) Reject(hash []byte, reason string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := s.now()
+	key := string(hash)
+	existing, ok := s.entries[key]
+	if ok {
+		existing.reason = reason
+		existing.expires = now.Add(s.ttl)
+		return nil
+	}
+	for len(s.order) != 0 && (len(s.order) >= s.capacity || s.entries[s.order[0]].expires.Before(now)) {
+		delete(s.entries, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.entries[key] = &entry{reason, now.Add(s.ttl)}
+	s.order = append(s.order, key)
+	return nil
+}
+
+func (s *MemoryStore) Reason(hash []byte) (string, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	existing, ok := s.entries[string(hash)]
+	if !ok || existing.expires.Before(s.now()) {
+		return "", false, nil
+	}
+	return existing.reason, true, nil
+}
+
+// RejectionKeyPrefix is prepended to the hex hash of a transaction in Redis
+const RejectionKeyPrefix = "rejected:"
+
+// RedisStore shares rejections between the binaries that accept transactions and the ones
+// that report their status, Redis expires them
+type RedisStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewRedisStore(client *redis.Client, ttl time.Duration) *RedisStore {
+	store := &RedisStore{client, ttl}
+	return store
+}
+
+func (s *RedisStore) Reject(hash []byte, reason string) error {
+	return s.client.Set(RejectionKeyPrefix+hex.EncodeToString(hash), reason, s.ttl).Err()
+}
+
+func (s *RedisStore) Reason(hash []byte) (string, bool, error) {
+	reason, err := s.client.Get(RejectionKeyPrefix + hex.EncodeToString(hash)).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return reason, true, nil
+}
@@ -0,0 +1,33 @@
+package rejections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreExpiresAndCapsRejections(t *testing.T) {
+	now := time.Unix(1000, 0)
+	store := NewMemoryStore(time.Minute, 2)
+	store.now = func() time.Time { return now }
+	store.Reject([]byte{0x01}, "Double spend")
+	reason, ok, _ := store.Reason([]byte{0x01})
+	if !ok || reason != "Double spend" {
+		t.Fatal("Rejection should be remembered")
+	}
+	store.Reject([]byte{0x02}, "Double spend")
+	store.Reject([]byte{0x03}, "Double spend")
+	if _, ok, _ := store.Reason([]byte{0x01}); ok {
+		t.Fatal("Oldest rejection should be dropped at capacity")
+	}
+	if _, ok, _ := store.Reason([]byte{0x03}); !ok {
+		t.Fatal("Latest rejection should be kept")
+	}
+	now = now.Add(2 * time.Minute)
+	if _, ok, _ := store.Reason([]byte{0x03}); ok {
+		t.Fatal("Rejection should expire")
+	}
+	store.Reject([]byte{0x04}, "Double spend")
+	if len(store.entries) != 1 {
+		t.Fatal("Expired rejections should be dropped")
+	}
+}
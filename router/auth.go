@@ -0,0 +1,154 @@
+package router
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// APIKeyHeader carries an API key, "Authorization: Bearer <key>" is accepted as well
+	APIKeyHeader = "X-API-Key"
+	// TimestampHeader is the unix time in seconds when a request was signed
+	TimestampHeader = "X-Timestamp"
+	// SignatureHeader is a hex encoded HMAC-SHA256 of the method, path, timestamp and body,
+	// each of them followed by a newline except for the body
+	SignatureHeader = "X-Signature"
+)
+
+// MaxSignatureAge is how far the timestamp of a signed request may be off the local clock
+const MaxSignatureAge = 5 * time.Minute
+
+var (
+	// ErrMissingCredentials is returned for a request without an API key or a signature
+	ErrMissingCredentials = errors.New("missing API key or signature")
+	// ErrInvalidAPIKey is returned for an API key that is not configured
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrInvalidSignature is returned for a signature that does not match the request
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrExpiredSignature is returned for a timestamp that is too far off
+	ErrExpiredSignature = errors.New("signature timestamp is too old or in the future")
+	// ErrReplayedSignature is returned for a signed request that was seen before
+	ErrReplayedSignature = errors.New("signature was already used")
+)
+
+// APIKeys accepts requests that carry one of the keys
+func APIKeys(keys []string) Authorizer {
+	return func(ctx *fasthttp.RequestCtx) error {
+		key := requestAPIKey(ctx)
+		if key == "" {
+			return ErrMissingCredentials
+		}
+		// every key is compared, so the time taken does not tell which one matched
+		matched := 0
+		for _, expected := range keys {
+			matched |= subtle.ConstantTimeCompare([]byte(key), []byte(expected))
+		}
+		if matched != 1 {
+			return ErrInvalidAPIKey
+		}
+		return nil
+	}
+}
+
+func requestAPIKey(ctx *fasthttp.RequestCtx) string {
+	key := string(ctx.Request.Header.Peek(APIKeyHeader))
+	if key != "" {
+		return key
+	}
+	authorization := string(ctx.Request.Header.Peek("Authorization"))
+	if strings.HasPrefix(authorization, "Bearer ") {
+		return strings.TrimPrefix(authorization, "Bearer ")
+	}
+	return ""
+}
+
+// HMAC accepts requests signed with the secret within MaxSignatureAge, a signature is
+// accepted only once
+func HMAC(secret []byte) Authorizer {
+	seen := &seenSignatures{signatures: make(map[string]time.Time)}
+	return func(ctx *fasthttp.RequestCtx) error {
+		timestamp := string(ctx.Request.Header.Peek(TimestampHeader))
+		signature, err := hex.DecodeString(string(ctx.Request.Header.Peek(SignatureHeader)))
+		if timestamp == "" || len(signature) == 0 {
+			return ErrMissingCredentials
+		}
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return ErrExpiredSignature
+		}
+		signedAt := time.Unix(unix, 0)
+		if time.Since(signedAt) > MaxSignatureAge || time.Until(signedAt) > MaxSignatureAge {
+			return ErrExpiredSignature
+		}
+		if !hmac.Equal(signature, SignRequest(secret, ctx.Method(), ctx.Path(), timestamp, ctx.PostBody())) {
+			return ErrInvalidSignature
+		}
+		if !seen.add(string(signature), signedAt) {
+			return ErrReplayedSignature
+		}
+		return nil
+	}
+}
+
+// SignRequest returns the signature that HMAC expects for a request
+func SignRequest(secret []byte, method []byte, path []byte, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(method)
+	mac.Write([]byte("\n"))
+	mac.Write(path)
+	mac.Write([]byte("\n" + timestamp + "\n"))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// AnyOf accepts requests accepted by any of the authorizers, the error of the first one
+// is returned if none of them does
+func AnyOf(authorizers ...Authorizer) Authorizer {
+	return func(ctx *fasthttp.RequestCtx) error {
+		var first error
+		for _, authorize := range authorizers {
+			err := authorize(ctx)
+			if err == nil {
+				return nil
+			}
+			if first == nil || first == ErrMissingCredentials {
+				first = err
+			}
+		}
+		return first
+	}
+}
+
+// seenSignatures remembers signatures until they expire anyway
+type seenSignatures struct {
+	mutex      sync.Mutex
+	signatures map[string]time.Time
+}
+
+func (s *seenSignatures) add(signature string, signedAt time.Time) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.signatures[signature]; ok {
+		return false
+	}
+	now := time.Now()
+	for old, oldSignedAt := range s.signatures {
+		if now.Sub(oldSignedAt) > MaxSignatureAge {
+			delete(s.signatures, old)
+		}
+	}
+	s.signatures[signature] = signedAt
+	return true
+}
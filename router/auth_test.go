@@ -0,0 +1,49 @@
+package router
+
+import (
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestOperatorAuthorizers(t *testing.T) {
+	secret := []byte("secret")
+	authorize := AnyOf(APIKeys([]string{"first", "second"}), HMAC(secret))
+
+	ctx := newRequest("POST", "/writeBlock", "block")
+	if authorize(ctx) != ErrMissingCredentials {
+		t.Fatal("Request without credentials should be rejected")
+	}
+	ctx.Request.Header.Set(APIKeyHeader, "third")
+	if authorize(ctx) != ErrInvalidAPIKey {
+		t.Fatal("Unknown API key should be rejected")
+	}
+	ctx = newRequest("POST", "/writeBlock", "block")
+	ctx.Request.Header.Set("Authorization", "Bearer second")
+	if authorize(ctx) != nil {
+		t.Fatal("Configured API key should be accepted")
+	}
+
+	sign := func(body string, signedAt time.Time) *fasthttp.RequestCtx {
+		ctx := newRequest("POST", "/writeBlock", body)
+		timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+		ctx.Request.Header.Set(TimestampHeader, timestamp)
+		ctx.Request.Header.Set(SignatureHeader, hex.EncodeToString(SignRequest(secret, []byte("POST"), []byte("/writeBlock"), timestamp, []byte("block"))))
+		return ctx
+	}
+	if authorize(sign("block", time.Now())) != nil {
+		t.Fatal("Signed request should be accepted")
+	}
+	if authorize(sign("block", time.Now())) != ErrReplayedSignature {
+		t.Fatal("Signature should be accepted only once")
+	}
+	if authorize(sign("other block", time.Now().Add(time.Second))) != ErrInvalidSignature {
+		t.Fatal("Signature should cover the body")
+	}
+	if authorize(sign("block", time.Now().Add(-2*MaxSignatureAge))) != ErrExpiredSignature {
+		t.Fatal("Old signature should be rejected")
+	}
+}
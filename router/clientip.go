@@ -0,0 +1,105 @@
+package router
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// clientIPKey holds the client address found by RealIP
+const clientIPKey = "clientIP"
+
+// TrustedProxies are the addresses allowed to report the address of a client in
+// X-Forwarded-For or X-Real-IP, e.g. the nginx in front of the API
+type TrustedProxies struct {
+	networks []*net.IPNet
+}
+
+// NewTrustedProxies accepts single IPs and CIDR ranges
+func NewTrustedProxies(proxies []string) (*TrustedProxies, error) {
+	trusted := &TrustedProxies{}
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, errors.New("Invalid trusted proxy " + proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			trusted.networks = append(trusted.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, errors.New("Invalid trusted proxy " + proxy)
+		}
+		trusted.networks = append(trusted.networks, network)
+	}
+	return trusted, nil
+}
+
+func (p *TrustedProxies) contains(ip net.IP) bool {
+	for _, network := range p.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP walks X-Forwarded-For back from the nearest hop and stops at the first address
+// that is not a trusted proxy, earlier entries may be forged by the client
+func (p *TrustedProxies) clientIP(ctx *fasthttp.RequestCtx) net.IP {
+	client := ctx.RemoteIP()
+	if !p.contains(client) {
+		return client
+	}
+	forwarded := ctx.Request.Header.Peek("X-Forwarded-For")
+	if len(forwarded) != 0 {
+		hops := strings.Split(string(forwarded), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				break
+			}
+			client = ip
+			if !p.contains(ip) {
+				break
+			}
+		}
+		return client
+	}
+	ip := net.ParseIP(strings.TrimSpace(string(ctx.Request.Header.Peek("X-Real-IP"))))
+	if ip != nil {
+		return ip
+	}
+	return client
+}
+
+// RealIP keeps the client address reported by a trusted proxy for ClientIP
+func RealIP(proxies *TrustedProxies) Middleware {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			ctx.SetUserValue(clientIPKey, proxies.clientIP(ctx).String())
+			next(ctx)
+		}
+	}
+}
+
+// ClientIP returns the address kept by RealIP or the remote address of the connection
+func ClientIP(ctx *fasthttp.RequestCtx) string {
+	ip, ok := ctx.UserValue(clientIPKey).(string)
+	if ok {
+		return ip
+	}
+	return ctx.RemoteIP().String()
+}
@@ -0,0 +1,52 @@
+package router
+
+import (
+	"net"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientIPTrustsOnlyProxies(t *testing.T) {
+	proxies, err := NewTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTrustedProxies([]string{"nginx"}); err == nil {
+		t.Fatal("Invalid proxy should be refused")
+	}
+	var seen string
+	handler := RealIP(proxies)(func(ctx *fasthttp.RequestCtx) {
+		seen = ClientIP(ctx)
+	})
+	call := func(remote string, forwardedFor string, realIP string) string {
+		request := &fasthttp.Request{}
+		if forwardedFor != "" {
+			request.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		if realIP != "" {
+			request.Header.Set("X-Real-IP", realIP)
+		}
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Init(request, &net.TCPAddr{IP: net.ParseIP(remote), Port: 1000}, nil)
+		handler(ctx)
+		return seen
+	}
+	if call("1.2.3.4", "5.6.7.8", "") != "1.2.3.4" {
+		t.Fatal("Headers of untrusted clients should be ignored")
+	}
+	if call("10.0.0.2", "6.6.6.6, 5.6.7.8, 10.0.0.3", "") != "5.6.7.8" {
+		t.Fatal("Nearest untrusted hop should be the client")
+	}
+	if call("192.168.1.1", "", "5.6.7.8") != "5.6.7.8" {
+		t.Fatal("X-Real-IP of a proxy should be used")
+	}
+	if call("10.0.0.2", "", "") != "10.0.0.2" {
+		t.Fatal("Proxy without headers is the client")
+	}
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Init(&fasthttp.Request{}, &net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 1000}, nil)
+	if ClientIP(ctx) != "1.2.3.4" {
+		t.Fatal("Without RealIP the connection address should be used")
+	}
+}
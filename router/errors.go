@@ -0,0 +1,34 @@
+package router
+
+import (
+	"encoding/json"
+
+	"github.com/valyala/fasthttp"
+)
+
+// ErrorResponse is the envelope of every failed request. Code is stable and meant
+// for programs, reason is for humans and may change
+type ErrorResponse struct {
+	Error  bool   `json:"error"`
+	Code   string `json:"code"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func WriteJSON(ctx *fasthttp.RequestCtx, statusCode int, response interface{}) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		WriteError(ctx, fasthttp.StatusInternalServerError, "internal_error", "failed to encode response")
+		return
+	}
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(statusCode)
+	ctx.SetBody(body)
+}
+
+func WriteError(ctx *fasthttp.RequestCtx, statusCode int, code string, reason string) {
+	response := ErrorResponse{Error: true, Code: code, Reason: reason}
+	body, _ := json.Marshal(response)
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(statusCode)
+	ctx.SetBody(body)
+}
@@ -0,0 +1,84 @@
+package router
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/matterinc/PlasmaBlockCreator/ratelimit"
+	"github.com/valyala/fasthttp"
+)
+
+// CORS allows browsers to call the API from any origin and answers preflight requests
+func CORS() Middleware {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			if string(ctx.Method()) != "OPTIONS" {
+				next(ctx)
+			} else {
+				ctx.SetStatusCode(fasthttp.StatusNoContent)
+			}
+			// set after the handler, so a handler resetting the response does not drop them
+			ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
+			ctx.Response.Header.Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
+			ctx.Response.Header.Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		}
+	}
+}
+
+// Logging prints a line per request with its status and duration
+func Logging() Middleware {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			start := time.Now()
+			next(ctx)
+			fmt.Println(string(ctx.Method()) + " " + string(ctx.Path()) + " " +
+				strconv.Itoa(ctx.Response.StatusCode()) + " " + time.Since(start).String())
+		}
+	}
+}
+
+// BodyLimit rejects requests with a body larger than maxSize. The server wide limit has to be
+// large enough for the biggest route, this one keeps small routes from reading large bodies
+func BodyLimit(maxSize int) Middleware {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			if ctx.Request.Header.ContentLength() > maxSize || len(ctx.PostBody()) > maxSize {
+				WriteError(ctx, fasthttp.StatusRequestEntityTooLarge, "body_too_large", "request body is larger than "+strconv.Itoa(maxSize)+" bytes")
+				return
+			}
+			next(ctx)
+		}
+	}
+}
+
+// Authorizer returns an error if a request is not allowed to reach a route
+type Authorizer func(ctx *fasthttp.RequestCtx) error
+
+// Auth passes only requests accepted by the authorizer
+func Auth(authorize Authorizer) Middleware {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			err := authorize(ctx)
+			if err != nil {
+				WriteError(ctx, fasthttp.StatusUnauthorized, "unauthorized", err.Error())
+				return
+			}
+			next(ctx)
+		}
+	}
+}
+
+// Limit answers with 503 while the shared limit of concurrent calls is reached
+func Limit(concurrency *ratelimit.Concurrency) Middleware {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			if !concurrency.Acquire() {
+				WriteError(ctx, fasthttp.StatusServiceUnavailable, "server_busy", "too many concurrent requests")
+				return
+			}
+			defer concurrency.Release()
+			next(ctx)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package router
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Middleware wraps a handler, e.g. to check a request before it is passed further
+type Middleware func(next fasthttp.RequestHandler) fasthttp.RequestHandler
+
+// Route is a method and a path that a router has a handler for
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Router dispatches requests by path and method. Router wide middleware runs for every
+// request including unknown routes, route middleware only for the route it was given with
+type Router struct {
+	routes     map[string]map[string]fasthttp.RequestHandler
+	middleware []Middleware
+}
+
+func New(middleware ...Middleware) *Router {
+	router := &Router{routes: make(map[string]map[string]fasthttp.RequestHandler), middleware: middleware}
+	return router
+}
+
+func (r *Router) Handle(method string, path string, handler fasthttp.RequestHandler, middleware ...Middleware) {
+	methods, ok := r.routes[path]
+	if !ok {
+		methods = make(map[string]fasthttp.RequestHandler)
+		r.routes[path] = methods
+	}
+	if _, exists := methods[method]; exists {
+		panic("Route " + method + " " + path + " is registered twice")
+	}
+	methods[method] = chain(handler, middleware)
+}
+
+func (r *Router) GET(path string, handler fasthttp.RequestHandler, middleware ...Middleware) {
+	r.Handle("GET", path, handler, middleware...)
+}
+
+func (r *Router) POST(path string, handler fasthttp.RequestHandler, middleware ...Middleware) {
+	r.Handle("POST", path, handler, middleware...)
+}
+
+// Routes lists registered routes sorted by path and method
+func (r *Router) Routes() []Route {
+	routes := []Route{}
+	for path, methods := range r.routes {
+		for method := range methods {
+			routes = append(routes, Route{method, path})
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// Handler returns a handler to be given to fasthttp.Server
+func (r *Router) Handler() fasthttp.RequestHandler {
+	return chain(r.dispatch, r.middleware)
+}
+
+func (r *Router) dispatch(ctx *fasthttp.RequestCtx) {
+	methods, ok := r.routes[string(ctx.Path())]
+	if !ok {
+		WriteError(ctx, fasthttp.StatusNotFound, "not_found", "route not found")
+		return
+	}
+	handler, ok := methods[string(ctx.Method())]
+	if ok {
+		handler(ctx)
+		return
+	}
+	ctx.Response.Header.Set("Allow", allowedMethods(methods))
+	if string(ctx.Method()) == "OPTIONS" {
+		ctx.SetStatusCode(fasthttp.StatusNoContent)
+		return
+	}
+	WriteError(ctx, fasthttp.StatusMethodNotAllowed, "method_not_allowed", "method is not allowed for this route")
+}
+
+func allowedMethods(methods map[string]fasthttp.RequestHandler) string {
+	allowed := []string{"OPTIONS"}
+	for method := range methods {
+		allowed = append(allowed, method)
+	}
+	sort.Strings(allowed)
+	return strings.Join(allowed, ", ")
+}
+
+// chain wraps a handler so that the first middleware runs first
+func chain(handler fasthttp.RequestHandler, middleware []Middleware) fasthttp.RequestHandler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
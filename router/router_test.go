@@ -0,0 +1,108 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func newRequest(method string, path string, body string) *fasthttp.RequestCtx {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(path)
+	ctx.Request.SetBodyString(body)
+	return ctx
+}
+
+func errorCode(t *testing.T, ctx *fasthttp.RequestCtx) string {
+	var response ErrorResponse
+	err := json.Unmarshal(ctx.Response.Body(), &response)
+	if err != nil || !response.Error {
+		t.Fatal("Response is not an error envelope")
+	}
+	return response.Code
+}
+
+func TestRouterDispatchesByMethodAndPath(t *testing.T) {
+	r := New(CORS())
+	r.POST("/echo", func(ctx *fasthttp.RequestCtx) {
+		WriteJSON(ctx, fasthttp.StatusOK, string(ctx.PostBody()))
+	}, BodyLimit(8))
+	handler := r.Handler()
+
+	ctx := newRequest("POST", "/echo", "hi")
+	handler(ctx)
+	if ctx.Response.StatusCode() != fasthttp.StatusOK || string(ctx.Response.Body()) != `"hi"` {
+		t.Fatal("Route was not called")
+	}
+	if len(ctx.Response.Header.Peek("Access-Control-Allow-Origin")) == 0 {
+		t.Fatal("CORS headers are missing")
+	}
+
+	ctx = newRequest("GET", "/echo", "")
+	handler(ctx)
+	if ctx.Response.StatusCode() != fasthttp.StatusMethodNotAllowed || errorCode(t, ctx) != "method_not_allowed" {
+		t.Fatal("Wrong method should be rejected")
+	}
+	if string(ctx.Response.Header.Peek("Allow")) != "OPTIONS, POST" {
+		t.Fatal("Allowed methods are not listed")
+	}
+
+	ctx = newRequest("POST", "/missing", "")
+	handler(ctx)
+	if ctx.Response.StatusCode() != fasthttp.StatusNotFound || errorCode(t, ctx) != "not_found" {
+		t.Fatal("Unknown route should be not found")
+	}
+	if len(ctx.Response.Header.Peek("Access-Control-Allow-Origin")) == 0 {
+		t.Fatal("CORS headers are missing for an unknown route")
+	}
+
+	ctx = newRequest("OPTIONS", "/echo", "")
+	handler(ctx)
+	if ctx.Response.StatusCode() != fasthttp.StatusNoContent {
+		t.Fatal("Preflight request should be answered")
+	}
+
+	ctx = newRequest("POST", "/echo", "too long body")
+	handler(ctx)
+	if ctx.Response.StatusCode() != fasthttp.StatusRequestEntityTooLarge || errorCode(t, ctx) != "body_too_large" {
+		t.Fatal("Large body should be rejected")
+	}
+}
+
+func TestRouteMiddlewareRunsInOrder(t *testing.T) {
+	order := ""
+	mark := func(name string) Middleware {
+		return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+			return func(ctx *fasthttp.RequestCtx) {
+				order += name
+				next(ctx)
+			}
+		}
+	}
+	denied := true
+	r := New(mark("a"))
+	r.POST("/operator", func(ctx *fasthttp.RequestCtx) {
+		order += "h"
+	}, mark("b"), Auth(func(ctx *fasthttp.RequestCtx) error {
+		if denied {
+			return errors.New("missing credentials")
+		}
+		return nil
+	}))
+	handler := r.Handler()
+
+	ctx := newRequest("POST", "/operator", "")
+	handler(ctx)
+	if ctx.Response.StatusCode() != fasthttp.StatusUnauthorized || errorCode(t, ctx) != "unauthorized" || order != "ab" {
+		t.Fatal("Unauthorized request should not reach the handler")
+	}
+	denied = false
+	order = ""
+	handler(newRequest("POST", "/operator", ""))
+	if order != "abh" {
+		t.Fatal("Middleware ran in a wrong order: " + order)
+	}
+}
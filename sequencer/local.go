@@ -0,0 +1,138 @@
+package sequencer
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+)
+
+var localCounterKey = []byte("sequencerCounter")
+
+// localBlockBytesKey holds a block number and the bytes taken in that block
+var localBlockBytesKey = []byte("sequencerBlockBytes")
+
+// LocalSequencer keeps the counter in the same storage as the UTXO set. Every call is a
+// separate transaction, so it does not need Redis and survives restarts, but each
+// counter costs a write to the hot key. Intended for single node and test setups
+type LocalSequencer struct {
+	db     storage.Database
+	limits BlockLimits
+}
+
+// NewLocalSequencer initializes the counter to InitialCounter if the storage does not have one yet
+func NewLocalSequencer(db storage.Database, limits BlockLimits) (*LocalSequencer, error) {
+	_, err := db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		value, err := tr.Get(localCounterKey).Get()
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			tr.Set(localCounterKey, encodeCounter(InitialCounter))
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sequencer := &LocalSequencer{db: db, limits: limits}
+	return sequencer, nil
+}
+
+func (s *LocalSequencer) Next(size int) (uint64, error) {
+	ret, err := s.db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		counter, err := readCounter(tr)
+		if err != nil {
+			return nil, err
+		}
+		counter++
+		blockNumber, transactionNumber := SplitCounter(counter)
+		usedBytes, err := readBlockBytes(tr, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		if !s.limits.Fits(transactionNumber, usedBytes, size) {
+			counter = LastCounterOfBlock(blockNumber) + 1
+			blockNumber++
+			usedBytes = 0
+		}
+		tr.Set(localCounterKey, encodeCounter(counter))
+		if s.limits.MaxBytes != 0 {
+			tr.Set(localBlockBytesKey, encodeBlockBytes(blockNumber, usedBytes+uint64(size)))
+		}
+		return counter, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ret.(uint64), nil
+}
+
+func (s *LocalSequencer) AdvanceToBlock(blockNumber uint32) error {
+	newCounterToSet := LastCounterOfBlock(blockNumber)
+	_, err := s.db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		counter, err := readCounter(tr)
+		if err != nil {
+			return nil, err
+		}
+		if newCounterToSet > counter {
+			tr.Set(localCounterKey, encodeCounter(newCounterToSet))
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func (s *LocalSequencer) Current() (uint64, error) {
+	ret, err := s.db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return readCounter(tr)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ret.(uint64), nil
+}
+
+func (s *LocalSequencer) Limits() BlockLimits {
+	return s.limits
+}
+
+func (s *LocalSequencer) Close() error {
+	return nil
+}
+
+func readCounter(tr storage.ReadTransaction) (uint64, error) {
+	value, err := tr.Get(localCounterKey).Get()
+	if err != nil {
+		return 0, err
+	}
+	if len(value) != 8 {
+		return 0, errors.New("Sequencer is not initialized")
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+func encodeCounter(counter uint64) []byte {
+	buffer := make([]byte, 8)
+	binary.BigEndian.PutUint64(buffer, counter)
+	return buffer
+}
+
+// readBlockBytes returns zero if the bytes were counted for another block
+func readBlockBytes(tr storage.ReadTransaction, blockNumber uint32) (uint64, error) {
+	value, err := tr.Get(localBlockBytesKey).Get()
+	if err != nil {
+		return 0, err
+	}
+	if len(value) != 12 || binary.BigEndian.Uint32(value) != blockNumber {
+		return 0, nil
+	}
+	return binary.BigEndian.Uint64(value[4:]), nil
+}
+
+func encodeBlockBytes(blockNumber uint32, usedBytes uint64) []byte {
+	buffer := make([]byte, 12)
+	binary.BigEndian.PutUint32(buffer, blockNumber)
+	binary.BigEndian.PutUint64(buffer[4:], usedBytes)
+	return buffer
+}
@@ -0,0 +1,110 @@
+package sequencer
+
+import (
+	"testing"
+
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+)
+
+func TestLocalSequencerAdvancesAndPersists(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	seq, err := NewLocalSequencer(db, BlockLimits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter, err := seq.Next(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counter != LastCounterOfBlock(0)+1 {
+		t.Fatal("First counter should be the first transaction of block 1")
+	}
+	err = seq.AdvanceToBlock(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = seq.AdvanceToBlock(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := seq.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current != LastCounterOfBlock(1) {
+		t.Fatal("Counter should not move back")
+	}
+	reopened, err := NewLocalSequencer(db, BlockLimits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter, err = reopened.Next(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counter != LastCounterOfBlock(1)+1 {
+		t.Fatal("Counter was not persisted")
+	}
+}
+
+func TestLocalSequencerRollsOverFullBlocks(t *testing.T) {
+	seq, err := NewLocalSequencer(storage.NewMemoryDatabase(), BlockLimits{MaxTransactions: 2, MaxBytes: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		size        int
+		blockNumber uint32
+	}{
+		{10, 1}, {10, 1},
+		// the transaction limit is reached
+		{10, 2},
+		// the byte limit would be passed
+		{95, 3},
+		// a large transaction gets an empty block
+		{200, 4},
+		{1, 5},
+	}
+	for i, next := range expected {
+		counter, err := seq.Next(next.size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blockNumber, _ := SplitCounter(counter)
+		if blockNumber != next.blockNumber {
+			t.Fatal("Transaction", i, "is in block", blockNumber, "instead of", next.blockNumber)
+		}
+	}
+}
+
+func TestVersionstampSequencerClosesBlocks(t *testing.T) {
+	db := storage.NewMemoryDatabase()
+	seq, err := NewVersionstampSequencer(db, BlockLimits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = seq.Next(0)
+	if err != ErrAssignedOnCommit {
+		t.Fatal("Versionstamp sequencer should not hand out counters")
+	}
+	err = seq.AdvanceToBlock(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return ReadBlockEpoch(tr)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.(uint32) != 2 {
+		t.Fatal("Block 2 should be open after closing block 1")
+	}
+	current, err := seq.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current != LastCounterOfBlock(2) {
+		t.Fatal("Current should cover the open block")
+	}
+}
@@ -0,0 +1,102 @@
+package sequencer
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	redis "github.com/go-redis/redis"
+)
+
+const redisCounterKey = "ctr"
+
+// redisBlockBytesKeyPrefix is followed by a block number, the key holds the bytes taken in the block
+const redisBlockBytesKeyPrefix = "ctrBytes:"
+
+// redisBlockBytesTTL keeps byte counters of old blocks from piling up
+const redisBlockBytesTTL = 24 * time.Hour
+
+// same script as in redisPrep/createRedis.js, raises the counter if the argument is larger
+const advanceScriptSource = "local c = tonumber(redis.call('get', KEYS[1])); if c then if tonumber(ARGV[1]) > c then redis.call('set', KEYS[1], ARGV[1]) return tonumber(ARGV[1]) - c else return c - tonumber(ARGV[1]) end else return 0 end"
+
+// RedisSequencer keeps the counter in Redis and can be shared by any number of processes
+type RedisSequencer struct {
+	client        *redis.Client
+	advanceScript *redis.Script
+	limits        BlockLimits
+}
+
+// NewRedisSequencer loads the counter script into Redis and initializes the counter to
+// InitialCounter if Redis does not have one yet, so the server does not have to be prepared by hand
+func NewRedisSequencer(client *redis.Client, limits BlockLimits) (*RedisSequencer, error) {
+	script := redis.NewScript(advanceScriptSource)
+	err := script.Load(client).Err()
+	if err != nil {
+		return nil, err
+	}
+	err = client.SetNX(redisCounterKey, strconv.FormatUint(InitialCounter, 10), 0).Err()
+	if err != nil {
+		return nil, err
+	}
+	sequencer := &RedisSequencer{client: client, advanceScript: script, limits: limits}
+	return sequencer, nil
+}
+
+// Next moves the counter past a full block and takes a counter again, the counter that
+// did not fit is left unused. Every process does the same, so the rollover needs no lock
+func (s *RedisSequencer) Next(size int) (uint64, error) {
+	for {
+		counter, err := s.client.Incr(redisCounterKey).Result()
+		if err != nil {
+			return 0, err
+		}
+		blockNumber, transactionNumber := SplitCounter(uint64(counter))
+		usedBytes := uint64(0)
+		if s.limits.MaxBytes != 0 {
+			key := redisBlockBytesKeyPrefix + strconv.FormatUint(uint64(blockNumber), 10)
+			pipeline := s.client.TxPipeline()
+			total := pipeline.IncrBy(key, int64(size))
+			pipeline.Expire(key, redisBlockBytesTTL)
+			_, err = pipeline.Exec()
+			if err != nil {
+				return 0, err
+			}
+			usedBytes = uint64(total.Val()) - uint64(size)
+		}
+		if s.limits.Fits(transactionNumber, usedBytes, size) {
+			return uint64(counter), nil
+		}
+		err = s.AdvanceToBlock(blockNumber)
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (s *RedisSequencer) AdvanceToBlock(blockNumber uint32) error {
+	newCounterToSet := LastCounterOfBlock(blockNumber)
+	err := s.advanceScript.Run(s.client, []string{redisCounterKey}, strconv.FormatUint(newCounterToSet, 10)).Err()
+	if err != nil {
+		return err
+	}
+	counterCheck, err := s.Current()
+	if err != nil {
+		return err
+	}
+	if counterCheck < newCounterToSet {
+		return errors.New("New counter is less than expected")
+	}
+	return nil
+}
+
+func (s *RedisSequencer) Current() (uint64, error) {
+	return s.client.Get(redisCounterKey).Uint64()
+}
+
+func (s *RedisSequencer) Limits() BlockLimits {
+	return s.limits
+}
+
+func (s *RedisSequencer) Close() error {
+	return s.client.Close()
+}
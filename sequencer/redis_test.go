@@ -0,0 +1,65 @@
+package sequencer
+
+import (
+	"os"
+	"testing"
+
+	redis "github.com/go-redis/redis"
+)
+
+// testRedisDB is flushed by the tests, REDIS_TEST_ADDR points them to a server
+const testRedisDB = 15
+
+func newTestRedisClient(t *testing.T) *redis.Client {
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		addr = "127.0.0.1:6379"
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr, DB: testRedisDB})
+	err := client.Ping().Err()
+	if err != nil {
+		client.Close()
+		t.Skip("No Redis at " + addr + ": " + err.Error())
+	}
+	err = client.FlushDB().Err()
+	if err != nil {
+		client.Close()
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestRedisSequencerStartsOnEmptyRedis(t *testing.T) {
+	client := newTestRedisClient(t)
+	seq, err := NewRedisSequencer(client, BlockLimits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer seq.Close()
+	current, err := seq.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current != InitialCounter {
+		t.Fatal("Empty Redis should start at the initial counter")
+	}
+	counter, err := seq.Next(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockNumber, transactionNumber := SplitCounter(counter)
+	if blockNumber != 1 || transactionNumber != 0 {
+		t.Fatal("First counter should be the first transaction of block 1")
+	}
+	reopened, err := NewRedisSequencer(client, BlockLimits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err = reopened.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current != counter {
+		t.Fatal("Existing counter should not be reset")
+	}
+}
@@ -0,0 +1,53 @@
+package sequencer
+
+import (
+	transaction "github.com/matterinc/PlasmaCommons/transaction"
+)
+
+// Sequencer hands out transaction counters. A counter is a block number in the
+// upper bytes and a transaction number in the lower TransactionNumberLength bytes
+type Sequencer interface {
+	// Next returns a new unique counter for a transaction of size encoded bytes,
+	// in the next block if the current one is full
+	Next(size int) (uint64, error)
+	// AdvanceToBlock moves the counter past the last counter of the given block,
+	// so no new transaction can be assigned into it
+	AdvanceToBlock(blockNumber uint32) error
+	// Current returns the last issued counter
+	Current() (uint64, error)
+	// Limits returns the size limits of a block
+	Limits() BlockLimits
+	Close() error
+}
+
+// BlockLimits cap the number of transactions in a block and the sum of their encoded
+// sizes, zero values leave a block unlimited
+type BlockLimits struct {
+	MaxTransactions uint32
+	MaxBytes        uint64
+}
+
+// Fits tells if a transaction of size bytes may take the given position in a block that
+// already has usedBytes. A transaction larger than MaxBytes still fits into an empty block
+func (l BlockLimits) Fits(transactionNumber uint32, usedBytes uint64, size int) bool {
+	if l.MaxTransactions != 0 && transactionNumber >= l.MaxTransactions {
+		return false
+	}
+	if l.MaxBytes != 0 && usedBytes != 0 && usedBytes+uint64(size) > l.MaxBytes {
+		return false
+	}
+	return true
+}
+
+// InitialCounter is the counter value before the first transaction of block 1
+var InitialCounter = LastCounterOfBlock(0)
+
+// LastCounterOfBlock returns the largest counter that belongs to the given block
+func LastCounterOfBlock(blockNumber uint32) uint64 {
+	return (uint64(blockNumber+1) << (transaction.TransactionNumberLength * 8)) - 1
+}
+
+// SplitCounter returns the block number and the transaction number of a counter
+func SplitCounter(counter uint64) (uint32, uint32) {
+	return uint32(counter >> (transaction.TransactionNumberLength * 8)), uint32(counter)
+}
@@ -0,0 +1,105 @@
+package sequencer
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+)
+
+// BlockEpochKey holds the number of the block that currently accepts new transactions
+var BlockEpochKey = []byte("blockEpoch")
+
+// ErrAssignedOnCommit is returned by VersionstampSequencer.Next, there are no counters to hand out
+var ErrAssignedOnCommit = errors.New("Transaction position is assigned on commit")
+
+// VersionstampSequencer does not hand out counters. Spending records are keyed by the
+// block epoch and a commit versionstamp instead, so the order inside a block is decided
+// by the storage itself and no external counter has to be kept in sync with it
+type VersionstampSequencer struct {
+	db     storage.Database
+	limits BlockLimits
+}
+
+// NewVersionstampSequencer opens block 1 if the storage does not have a block epoch yet
+func NewVersionstampSequencer(db storage.Database, limits BlockLimits) (*VersionstampSequencer, error) {
+	_, err := db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		value, err := tr.Get(BlockEpochKey).Get()
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			tr.Set(BlockEpochKey, encodeBlockEpoch(1))
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sequencer := &VersionstampSequencer{db: db, limits: limits}
+	return sequencer, nil
+}
+
+func (s *VersionstampSequencer) Next(size int) (uint64, error) {
+	return 0, ErrAssignedOnCommit
+}
+
+// AdvanceToBlock closes the given block by moving the epoch to the next one. Writers read
+// the epoch in the same transaction as they write a spending record, so a record for the
+// closed block can not be committed after this returns
+func (s *VersionstampSequencer) AdvanceToBlock(blockNumber uint32) error {
+	_, err := s.db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		epoch, err := ReadBlockEpoch(tr)
+		if err != nil {
+			return nil, err
+		}
+		if blockNumber+1 > epoch {
+			tr.Set(BlockEpochKey, encodeBlockEpoch(blockNumber+1))
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// Current returns the last counter of the open block, as any position in it may be taken
+func (s *VersionstampSequencer) Current() (uint64, error) {
+	ret, err := s.db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return ReadBlockEpoch(tr)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return LastCounterOfBlock(ret.(uint32)), nil
+}
+
+// Limits are applied when a block is assembled, records that do not fit are moved to the next block
+func (s *VersionstampSequencer) Limits() BlockLimits {
+	return s.limits
+}
+
+func (s *VersionstampSequencer) Close() error {
+	return nil
+}
+
+// AssignedOnCommit tells if the sequencer leaves ordering to storage versionstamps
+func AssignedOnCommit(seq Sequencer) bool {
+	_, ok := seq.(*VersionstampSequencer)
+	return ok
+}
+
+func ReadBlockEpoch(tr storage.ReadTransaction) (uint32, error) {
+	value, err := tr.Get(BlockEpochKey).Get()
+	if err != nil {
+		return 0, err
+	}
+	if len(value) != 4 {
+		return 0, errors.New("Block epoch is not initialized")
+	}
+	return binary.BigEndian.Uint32(value), nil
+}
+
+func encodeBlockEpoch(blockNumber uint32) []byte {
+	buffer := make([]byte, 4)
+	binary.BigEndian.PutUint32(buffer, blockNumber)
+	return buffer
+}
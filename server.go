@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -12,37 +11,32 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/matterinc/PlasmaBlockCreator/foundationdb"
 
 	"github.com/matterinc/PlasmaCommons/transaction"
 
 	"github.com/apple/foundationdb/bindings/go/src/fdb"
 	env "github.com/caarlos0/env"
-	redis "github.com/go-redis/redis"
+	"github.com/matterinc/PlasmaBlockCreator/configs"
 	handlers "github.com/matterinc/PlasmaBlockCreator/handlers"
+	"github.com/matterinc/PlasmaBlockCreator/router"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/reuseport"
 )
 
+// StartupConfig keeps the concurrency defaults of the all in one server
 type StartupConfig struct {
-	Port                  int    `env:"PORT" envDefault:"3001"`
-	FdbRewriteClusterFile bool   `env:"FDB_REWRITE" envDefault:"false"`
-	FdbClusterFilePath    string `env:"FDB_CLUSTER_FILE_PATH" envDefault:""`
-	RedisHost             string `env:"REDIS_HOST" envDefault:"127.0.0.1"`
-	RedisPort             int    `env:"REDIS_PORT" envDefault:"6379"`
-	RedisPassword         string `env:"REDIS_PASSWORD" envDefault:""`
-	FundingTXSigningKey   string `env:"FUNDINGTX_ETH_KEY" envDefault:"0xc87509a1c067bbde78beb793e6fa76530b6382a4c0241e5e4a9ec0a0f44dc0d3"`
-	BlockSigningKey       string `env:"BLOCK_ETH_KEY" envDefault:"0xc87509a1c067bbde78beb793e6fa76530b6382a4c0241e5e4a9ec0a0f44dc0d3"`
-	DatabaseConcurrency   int    `env:"FDB_CONCURRENCY" envDefault:"-1"`
-	ECRecoverConcurrency  int    `env:"EC_CONCURRENCY" envDefault:"-1"`
-	MaxProc               int    `env:"GOMAXPROCS" envDefault:"-1"`
+	DatabaseConcurrency  int `env:"FDB_CONCURRENCY" envDefault:"-1"`
+	ECRecoverConcurrency int `env:"EC_CONCURRENCY" envDefault:"-1"`
+	MaxProc              int `env:"GOMAXPROCS" envDefault:"-1"`
 }
 
 const defaultDatabaseConcurrency = 100000
 const defaultECRecoverConcurrency = 30000
 
 func main() {
+	fdb.MustAPIVersion(520)
+
 	cfg := StartupConfig{}
 	err := env.Parse(&cfg)
 	if err != nil {
@@ -50,39 +44,83 @@ func main() {
 		os.Exit(1)
 	}
 	fmt.Printf("%+v\n", cfg)
-	foundDB, err := initDB(cfg)
+	httpConfig, redisConfig, _, databaseConfig, signatureConfig, err := configs.ParseConfigs()
 	if err != nil {
 		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     cfg.RedisHost + ":" + strconv.Itoa(cfg.RedisPort),
-		Password: cfg.RedisPassword,
-		DB:       0,
-	})
-	defer redisClient.Close()
-
-	fmt.Println("Testing for the counter in Redis")
-	redisCounter, err := redisClient.Get("ctr").Uint64()
+	storageConfig, err := configs.ParseStorageConfig()
 	if err != nil {
-		log.Println(err)
-		redisClient.Close()
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	sequencerConfig, err := configs.ParseSequencerConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	authConfig, err := configs.ParseAuthConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	operatorAuth, err := configs.InitOperatorAuth(authConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	blockSigner, err := configs.InitBlockSigner(signatureConfig, authConfig.DevMode)
+	if err != nil {
+		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
-	fmt.Println("Redis counter = ", strconv.FormatUint(redisCounter, 10))
+	fundingTXSigner, err := configs.InitFundingTXSigner(signatureConfig, authConfig.DevMode)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	rateLimitConfig, err := configs.ParseRateLimitConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	rejectionConfig, err := configs.ParseRejectionConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	eventsConfig, err := configs.ParseEventsConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	blockArchiveConfig, err := configs.ParseBlockArchiveConfig()
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	foundDB, err := configs.InitStorage(storageConfig, databaseConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	seq, err := configs.InitSequencer(sequencerConfig, redisConfig, foundDB)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	defer seq.Close()
 
-	fmt.Println("Testing for the database")
-	maxCounterInDatabase, err := foundationdb.GetMaxTransactionCounter(foundDB)
+	err = foundationdb.CheckSequencer(foundDB, seq)
 	if err != nil {
 		log.Println(err)
-		redisClient.Close()
+		seq.Close()
 		os.Exit(1)
 	}
-	fmt.Println("Database counter = ", strconv.FormatUint(maxCounterInDatabase, 10))
 
-	if maxCounterInDatabase > redisCounter {
-		log.Println("Counters mismatch")
-		redisClient.Close()
+	err = configs.InitLastBlockHash(blockArchiveConfig, foundDB)
+	if err != nil {
+		log.Printf("%+v\n", err)
 		os.Exit(1)
 	}
 
@@ -102,42 +140,81 @@ func main() {
 	fmt.Println("ECRecover concurrency = " + strconv.Itoa(ECRecoverConcurrency))
 	fmt.Println("FDB concurrency = " + strconv.Itoa(DatabaseConcurrency))
 
+	publisher, broker, err := configs.InitEvents(eventsConfig, redisConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	rejected, err := configs.InitRejections(rejectionConfig, redisConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+
 	transactionParser := transaction.NewTransactionParser(ECRecoverConcurrency)
-	sendRawTXHandler := handlers.NewSendRawTXHandler(foundDB, redisClient, transactionParser, DatabaseConcurrency)
-	createUTXOHandler := handlers.NewCreateUTXOHandler(foundDB)
+	sendRawTXHandler := handlers.NewSendRawTXHandler(foundDB, seq, transactionParser, DatabaseConcurrency, publisher, rejected)
+	sendRawTXHandler.SetRateLimits(configs.InitRateLimits(rateLimitConfig))
+	sendRawTXsHandler := handlers.NewSendRawTXsHandler(sendRawTXHandler)
 	listUTXOsHandler := handlers.NewListUTXOsHandler(foundDB)
-	assembleBlockHandler := handlers.NewAssembleBlockHandler(foundDB, redisClient, common.FromHex(cfg.BlockSigningKey))
-	createFundingTXhandler := handlers.NewCreateFundingTXHandler(foundDB, redisClient, common.FromHex(cfg.FundingTXSigningKey))
-	writeBlockHandler := handlers.NewWriteBlockHandler(foundDB)
+	getTransactionHandler := handlers.NewGetTransactionHandler(foundDB, rejected)
+	getTransactionStatusHandler := handlers.NewGetTransactionStatusHandler(foundDB, rejected)
+	getBlockHandler := handlers.NewGetBlockHandler(foundDB)
+	getBlockHeaderHandler := handlers.NewGetBlockHeaderHandler(foundDB)
+	getProofHandler := handlers.NewGetProofHandler(foundDB, rejected)
+	getBalanceHandler := handlers.NewGetBalanceHandler(foundDB)
+	subscribeHandler := handlers.NewSubscribeHandler(broker)
+	jsonRPCHandler := handlers.NewJSONRPCHandler()
+	jsonRPCHandler.RegisterSendMethods(sendRawTXHandler)
+	jsonRPCHandler.RegisterReadMethods(foundDB, rejected)
+	assembleBlockHandler := handlers.NewAssembleBlockHandler(foundDB, seq, blockSigner)
+	previewBlockHandler := handlers.NewPreviewBlockHandler(foundDB, seq)
+	createFundingTXhandler := handlers.NewCreateFundingTXHandler(foundDB, seq, fundingTXSigner)
+	writeBlockHandler := handlers.NewWriteBlockHandler(foundDB, publisher)
 	lastBlockHandler := handlers.NewLastBlockHandler(foundDB)
-	processNormalExitHandler := handlers.NewWithdrawTXHandler(foundDB)
+	processNormalExitHandler := handlers.NewWithdrawTXHandler(foundDB, publisher)
 	processDepositExitHandler := handlers.NewDepositWithdrawTXHandler(foundDB)
-	m := func(ctx *fasthttp.RequestCtx) {
-		switch string(ctx.Path()) {
-		case "/sendRawTX":
-			sendRawTXHandler.HandlerFunc(ctx)
-		case "/createUTXO":
-			createUTXOHandler.HandlerFunc(ctx) // debug only
-		case "/listUTXOs":
-			listUTXOsHandler.HandlerFunc(ctx)
-		case "/assembleBlock":
-			assembleBlockHandler.HandlerFunc(ctx)
-		case "/createFundingTX":
-			createFundingTXhandler.HandlerFunc(ctx) // legacy
-		case "/lastWrittenBlock":
-			lastBlockHandler.HandlerFunc(ctx)
-		case "/writeBlock":
-			writeBlockHandler.HandlerFunc(ctx)
-		case "/processEvent/DepositEvent":
-			createFundingTXhandler.HandlerFunc(ctx)
-		case "/processEvent/ExitStartedEvent":
-			processNormalExitHandler.HandlerFunc(ctx)
-		case "/processEvent/DepositWithdrawStartedEvent":
-			processDepositExitHandler.HandlerFunc(ctx)
-		default:
-			ctx.Error("Not found", fasthttp.StatusNotFound)
-		}
+	middleware := []router.Middleware{}
+	if httpConfig.LogRequests {
+		middleware = append(middleware, router.Logging())
 	}
+	middleware = append(middleware, router.CORS())
+	realIP, err := configs.InitTrustedProxies(httpConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	if realIP != nil {
+		middleware = append(middleware, realIP)
+	}
+	validate, err := configs.InitValidation(httpConfig)
+	if err != nil {
+		log.Printf("%+v\n", err)
+		os.Exit(1)
+	}
+	r := router.New(middleware...)
+	routes := handlers.Routes{SendRawTX: sendRawTXHandler,
+		SendRawTXs:           sendRawTXsHandler,
+		ListUTXOs:            listUTXOsHandler,
+		GetTransaction:       getTransactionHandler,
+		GetTransactionStatus: getTransactionStatusHandler,
+		GetBlock:             getBlockHandler,
+		GetBlockHeader:       getBlockHeaderHandler,
+		GetProof:             getProofHandler,
+		GetBalance:           getBalanceHandler,
+		Subscribe:            subscribeHandler,
+		JSONRPC:              jsonRPCHandler,
+		AssembleBlock:        assembleBlockHandler,
+		PreviewBlock:         previewBlockHandler,
+		LegacyFundingTX:      createFundingTXhandler,
+		LastBlock:            lastBlockHandler,
+		WriteBlock:           writeBlockHandler,
+		Deposit:              createFundingTXhandler,
+		Exit:                 processNormalExitHandler,
+		DepositExit:          processDepositExitHandler}
+	if authConfig.DevMode {
+		routes.CreateUTXO = handlers.NewCreateUTXOHandler(foundDB)
+	}
+	routes.Register(r, validate, operatorAuth)
 
 	server := fasthttp.Server{
 		Name:               "Plasma",
@@ -145,13 +222,13 @@ func main() {
 		MaxConnsPerIP:      100000,
 		WriteTimeout:       time.Second * 15,
 		ReadTimeout:        time.Second * 15,
-		Handler:            m,
+		Handler:            r.Handler(),
 		MaxRequestBodySize: 500000000,
 	}
 
 	var listener net.Listener
 	go func() {
-		listener, err = reuseport.Listen("tcp4", "0.0.0.0"+":"+strconv.Itoa(cfg.Port))
+		listener, err = reuseport.Listen("tcp4", "0.0.0.0"+":"+strconv.Itoa(httpConfig.Port))
 		if err != nil {
 			panic("Can not bind")
 		}
@@ -160,7 +237,7 @@ func main() {
 		}
 	}()
 
-	fmt.Println("Started to listen on " + "0.0.0.0" + ":" + strconv.Itoa(cfg.Port))
+	fmt.Println("Started to listen on " + "0.0.0.0" + ":" + strconv.Itoa(httpConfig.Port))
 	wait := time.Second * 15
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
@@ -171,29 +248,3 @@ func main() {
 	log.Println("Shutting down")
 	os.Exit(0)
 }
-
-func initDB(config StartupConfig) (*fdb.Database, error) {
-	fdb.MustAPIVersion(520)
-	err := fdb.StartNetwork()
-	if err != nil {
-		return nil, err
-	}
-	if config.FdbRewriteClusterFile == false {
-		db := fdb.MustOpenDefault()
-		return &db, nil
-	}
-	if config.FdbClusterFilePath == "" {
-		return nil, errors.New("Empty content for cluster file rewriting")
-	}
-
-	clusterFileName := config.FdbClusterFilePath
-	cluster, err := fdb.CreateCluster(clusterFileName)
-	if err != nil {
-		return nil, err
-	}
-	db, err := cluster.OpenDatabase([]byte("DB"))
-	if err != nil {
-		return nil, err
-	}
-	return &db, nil
-}
@@ -0,0 +1,52 @@
+package signer
+
+import (
+	"errors"
+	"io/ioutil"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/matterinc/PlasmaCommons/block"
+	"github.com/matterinc/PlasmaCommons/transaction"
+)
+
+// KeySigner signs with a private key in memory
+type KeySigner struct {
+	key     []byte
+	address common.Address
+}
+
+func NewKeySigner(key []byte) (*KeySigner, error) {
+	privateKey, err := crypto.ToECDSA(key)
+	if err != nil {
+		return nil, errors.New("Invalid signing key")
+	}
+	signer := &KeySigner{key: key, address: crypto.PubkeyToAddress(privateKey.PublicKey)}
+	return signer, nil
+}
+
+// NewKeystoreSigner decrypts an Ethereum keystore file with the passphrase
+func NewKeystoreSigner(path string, passphrase string) (*KeySigner, error) {
+	keyJSON, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, errors.New("Failed to decrypt keystore " + path)
+	}
+	return NewKeySigner(crypto.FromECDSA(key.PrivateKey))
+}
+
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *KeySigner) SignBlock(b *block.Block) error {
+	return b.Sign(s.key)
+}
+
+func (s *KeySigner) SignTransaction(tx *transaction.SignedTransaction) error {
+	return tx.Sign(s.key)
+}
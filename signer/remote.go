@@ -0,0 +1,153 @@
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/matterinc/PlasmaCommons/block"
+	"github.com/matterinc/PlasmaCommons/transaction"
+	"github.com/valyala/fasthttp"
+)
+
+// A signing service answers
+//   GET  /address          with {"address": "0x..."}
+//   POST /signBlock        {"data": "0x<serialized block>"} with {"v": "0x..", "r": "0x..", "s": "0x.."}
+//   POST /signTransaction  {"data": "0x<RLP of the transaction>"} with the same
+// The whole block is sent, so the service can check what it signs
+
+type addressResponse struct {
+	Address string `json:"address"`
+}
+
+type signRequest struct {
+	Data string `json:"data"`
+}
+
+type signatureResponse struct {
+	V string `json:"v"`
+	R string `json:"r"`
+	S string `json:"s"`
+}
+
+// RemoteSigner has blocks and transactions signed by a signing service over HTTP
+type RemoteSigner struct {
+	url     string
+	client  *fasthttp.Client
+	timeout time.Duration
+	address common.Address
+}
+
+// NewRemoteSigner asks the service at url for its address
+func NewRemoteSigner(url string, timeout time.Duration) (*RemoteSigner, error) {
+	return newRemoteSigner(url, &fasthttp.Client{}, timeout)
+}
+
+func newRemoteSigner(url string, client *fasthttp.Client, timeout time.Duration) (*RemoteSigner, error) {
+	signer := &RemoteSigner{url: url, client: client, timeout: timeout}
+	var response addressResponse
+	err := signer.call("/address", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	if !common.IsHexAddress(response.Address) {
+		return nil, errors.New("Signing service returned an invalid address")
+	}
+	signer.address = common.HexToAddress(response.Address)
+	return signer, nil
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignBlock checks that the signature recovers to the address of the service
+func (s *RemoteSigner) SignBlock(b *block.Block) error {
+	rawBlock, err := b.Serialize()
+	if err != nil {
+		return err
+	}
+	var response signatureResponse
+	err = s.call("/signBlock", &signRequest{common.ToHex(rawBlock)}, &response)
+	if err != nil {
+		return err
+	}
+	err = response.apply(&b.BlockHeader.V, &b.BlockHeader.R, &b.BlockHeader.S)
+	if err != nil {
+		return err
+	}
+	from, err := blockSigner(b.BlockHeader)
+	if err != nil {
+		return err
+	}
+	if from != s.address {
+		return errors.New("Signing service signed with another key")
+	}
+	return nil
+}
+
+// SignTransaction checks that the signature recovers to the address of the service
+func (s *RemoteSigner) SignTransaction(tx *transaction.SignedTransaction) error {
+	rawTX, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return err
+	}
+	var response signatureResponse
+	err = s.call("/signTransaction", &signRequest{common.ToHex(rawTX)}, &response)
+	if err != nil {
+		return err
+	}
+	err = response.apply(&tx.V, &tx.R, &tx.S)
+	if err != nil {
+		return err
+	}
+	from, err := tx.GetFrom()
+	if err != nil {
+		return err
+	}
+	if from != s.address {
+		return errors.New("Signing service signed with another key")
+	}
+	return nil
+}
+
+func (s *RemoteSigner) call(path string, request interface{}, response interface{}) error {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI(s.url + path)
+	if request != nil {
+		body, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		req.Header.SetMethod("POST")
+		req.Header.SetContentType("application/json")
+		req.SetBody(body)
+	}
+	err := s.client.DoTimeout(req, resp, s.timeout)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() != fasthttp.StatusOK {
+		return errors.New("Signing service answered with status " + strconv.Itoa(resp.StatusCode()))
+	}
+	return json.Unmarshal(resp.Body(), response)
+}
+
+func (r *signatureResponse) apply(v *[1]byte, rValue *[32]byte, sValue *[32]byte) error {
+	vBytes := common.FromHex(r.V)
+	rBytes := common.FromHex(r.R)
+	sBytes := common.FromHex(r.S)
+	if len(vBytes) != len(v) || len(rBytes) != len(rValue) || len(sBytes) != len(sValue) {
+		return errors.New("Signing service returned a malformed signature")
+	}
+	copy(v[:], vBytes)
+	copy(rValue[:], rBytes)
+	copy(sValue[:], sBytes)
+	return nil
+}
@@ -0,0 +1,94 @@
+package signer
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/matterinc/PlasmaCommons/block"
+	"github.com/matterinc/PlasmaCommons/transaction"
+	types "github.com/matterinc/PlasmaCommons/types"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+var testKey = common.FromHex("0xae6ae8e5ccbfb04590405997ee2d52d2b330726137b875053c36d94e974d162f")
+var testRecipient = common.HexToAddress("0x627306090abab3a6e1400e9345bc60c78a8bef57")
+
+func TestRemoteSignerSignsFundingTransactions(t *testing.T) {
+	local, err := NewKeySigner(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener := fasthttputil.NewInmemoryListener()
+	defer listener.Close()
+	go fasthttp.Serve(listener, NewService(local).HandlerFunc)
+	client := &fasthttp.Client{Dial: func(addr string) (net.Conn, error) {
+		return listener.Dial()
+	}}
+	remote, err := newRemoteSigner("http://signer", client, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remote.Address() != local.Address() {
+		t.Fatal("Remote signer reports a wrong address")
+	}
+	fundingTX, err := CreateFundingTX(remote, testRecipient, types.NewBigInt(1000), types.NewBigInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := fundingTX.GetFrom()
+	if err != nil || from != local.Address() {
+		t.Fatal("Funding transaction should be signed by the service key")
+	}
+	// the transaction was created with the default key, its signature is replaced completely
+	defaultTX, err := transaction.CreateRawFundingTX(testRecipient, types.NewBigInt(1000), types.NewBigInt(1), common.FromHex(DefaultKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fundingTX.R == defaultTX.R || fundingTX.S == defaultTX.S {
+		t.Fatal("Default signature should not be left in the funding transaction")
+	}
+
+	newBlock, err := block.NewBlock(1, []*transaction.SignedTransaction{fundingTX}, make([]byte, block.PreviousBlockHashLength))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = remote.SignBlock(newBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err = blockSigner(newBlock.BlockHeader)
+	if err != nil || from != local.Address() {
+		t.Fatal("Block should be signed by the service key")
+	}
+	// a service that reports one address and signs with another key is refused
+	remote.address = testRecipient
+	if remote.SignBlock(newBlock) == nil {
+		t.Fatal("Block signed with another key should be refused")
+	}
+	if _, err = CreateFundingTX(remote, testRecipient, types.NewBigInt(1000), types.NewBigInt(2)); err == nil {
+		t.Fatal("Transaction signed with another key should be refused")
+	}
+}
+
+func TestDefaultKeyNeedsDevMode(t *testing.T) {
+	s, err := NewKeySigner(common.FromHex(DefaultKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if CheckNotDefault(s, false) == nil {
+		t.Fatal("Default key should be refused outside of dev mode")
+	}
+	if CheckNotDefault(s, true) != nil {
+		t.Fatal("Default key should be allowed in dev mode")
+	}
+	other, err := NewKeySigner(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if CheckNotDefault(other, false) != nil {
+		t.Fatal("Other keys should be allowed")
+	}
+}
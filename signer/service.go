@@ -0,0 +1,84 @@
+package signer
+
+import (
+	"encoding/json"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/matterinc/PlasmaBlockCreator/router"
+	"github.com/matterinc/PlasmaCommons/block"
+	"github.com/matterinc/PlasmaCommons/transaction"
+	"github.com/valyala/fasthttp"
+)
+
+// Service answers RemoteSigner with a local signer. It stands in for a signing service in
+// tests and development setups and has no authentication, do not expose it
+type Service struct {
+	signer Signer
+}
+
+func NewService(signer Signer) *Service {
+	service := &Service{signer: signer}
+	return service
+}
+
+func (s *Service) HandlerFunc(ctx *fasthttp.RequestCtx) {
+	switch string(ctx.Path()) {
+	case "/address":
+		router.WriteJSON(ctx, fasthttp.StatusOK, addressResponse{s.signer.Address().Hex()})
+	case "/signBlock":
+		raw, ok := readSignRequest(ctx)
+		if !ok {
+			return
+		}
+		newBlock, err := block.NewBlockFromBytes(raw)
+		if err != nil || newBlock == nil {
+			router.WriteError(ctx, fasthttp.StatusBadRequest, "invalid_request", "invalid block")
+			return
+		}
+		err = s.signer.SignBlock(newBlock)
+		if err != nil {
+			router.WriteError(ctx, fasthttp.StatusInternalServerError, "signing_failed", "failed to sign block")
+			return
+		}
+		header := newBlock.BlockHeader
+		writeSignature(ctx, header.V[:], header.R[:], header.S[:])
+	case "/signTransaction":
+		raw, ok := readSignRequest(ctx)
+		if !ok {
+			return
+		}
+		var tx transaction.SignedTransaction
+		err := rlp.DecodeBytes(raw, &tx)
+		if err != nil {
+			router.WriteError(ctx, fasthttp.StatusBadRequest, "invalid_request", "invalid transaction")
+			return
+		}
+		err = s.signer.SignTransaction(&tx)
+		if err != nil {
+			router.WriteError(ctx, fasthttp.StatusInternalServerError, "signing_failed", "failed to sign transaction")
+			return
+		}
+		writeSignature(ctx, tx.V[:], tx.R[:], tx.S[:])
+	default:
+		router.WriteError(ctx, fasthttp.StatusNotFound, "not_found", "not found")
+	}
+}
+
+func readSignRequest(ctx *fasthttp.RequestCtx) ([]byte, bool) {
+	if !ctx.IsPost() {
+		router.WriteError(ctx, fasthttp.StatusMethodNotAllowed, "method_not_allowed", "method not allowed")
+		return nil, false
+	}
+	var request signRequest
+	err := json.Unmarshal(ctx.PostBody(), &request)
+	if err != nil {
+		router.WriteError(ctx, fasthttp.StatusBadRequest, "invalid_request", "invalid request")
+		return nil, false
+	}
+	return common.FromHex(request.Data), true
+}
+
+func writeSignature(ctx *fasthttp.RequestCtx, v []byte, r []byte, s []byte) {
+	router.WriteJSON(ctx, fasthttp.StatusOK, signatureResponse{common.ToHex(v), common.ToHex(r), common.ToHex(s)})
+}
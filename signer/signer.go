@@ -0,0 +1,78 @@
+package signer
+
+import (
+	"errors"
+	"strconv"
+
+	common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/matterinc/PlasmaCommons/block"
+	"github.com/matterinc/PlasmaCommons/transaction"
+	types "github.com/matterinc/PlasmaCommons/types"
+)
+
+// Signer signs assembled blocks and funding transactions, the key does not have to be
+// in the process. Implementations are safe for concurrent use
+type Signer interface {
+	// Address is the account the signatures recover to
+	Address() common.Address
+	SignBlock(b *block.Block) error
+	SignTransaction(tx *transaction.SignedTransaction) error
+}
+
+// DefaultKey is the well known key of development setups
+const DefaultKey = "0xc87509a1c067bbde78beb793e6fa76530b6382a4c0241e5e4a9ec0a0f44dc0d3"
+
+// DefaultAddress is the account of DefaultKey
+var DefaultAddress = common.HexToAddress("0x627306090abab3a6e1400e9345bc60c78a8bef57")
+
+// CheckNotDefault refuses a signer with the default key unless devMode is set
+func CheckNotDefault(s Signer, devMode bool) error {
+	if s.Address() == DefaultAddress && !devMode {
+		return errors.New("Signing with the default key is allowed in dev mode only")
+	}
+	return nil
+}
+
+// CreateFundingTX returns a signed funding transaction for a deposit. PlasmaCommons only
+// creates signed funding transactions, for other signers than KeySigner the transaction is
+// created with the default key and signed again. Signing sets all of V, R and S, so nothing
+// of the default signature is left
+func CreateFundingTX(s Signer, to common.Address, value *types.BigInt, depositIndex *types.BigInt) (*transaction.SignedTransaction, error) {
+	if keySigner, ok := s.(*KeySigner); ok {
+		return transaction.CreateRawFundingTX(to, value, depositIndex, keySigner.key)
+	}
+	fundingTX, err := transaction.CreateRawFundingTX(to, value, depositIndex, common.FromHex(DefaultKey))
+	if err != nil {
+		return nil, err
+	}
+	err = s.SignTransaction(fundingTX)
+	if err != nil {
+		return nil, err
+	}
+	return fundingTX, nil
+}
+
+// blockSigner recovers the account of a block signature. The header fields before the
+// signature are signed as an Ethereum personal message
+func blockSigner(header *block.BlockHeader) (common.Address, error) {
+	signed := []byte{}
+	signed = append(signed, header.BlockNumber[:]...)
+	signed = append(signed, header.NumberOfTransactions[:]...)
+	signed = append(signed, header.ParentHash[:]...)
+	signed = append(signed, header.MerkleTreeRoot[:]...)
+	hash := crypto.Keccak256([]byte("\x19Ethereum Signed Message:\n"+strconv.Itoa(len(signed))), signed)
+	signature := []byte{}
+	signature = append(signature, header.R[:]...)
+	signature = append(signature, header.S[:]...)
+	v := header.V[0]
+	if v >= 27 {
+		v -= 27
+	}
+	signature = append(signature, v)
+	publicKey, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
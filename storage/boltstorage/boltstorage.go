@@ -0,0 +1,248 @@
+package boltstorage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+	bolt "go.etcd.io/bbolt"
+)
+
+var bucketName = []byte("plasma")
+
+// Database implements storage.Database on top of an embedded bbolt file. Bolt allows
+// only one read-write transaction at a time, so Transact is serializable the same way
+// FoundationDB transactions are and UTXOWriter double spend checks hold
+type Database struct {
+	db *bolt.DB
+}
+
+func Open(path string) (*Database, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	database := &Database{db: db}
+	return database, nil
+}
+
+func (d *Database) Close() error {
+	return d.db.Close()
+}
+
+func (d *Database) Transact(f func(tr storage.Transaction) (interface{}, error)) (interface{}, error) {
+	var ret interface{}
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		tr := newTransaction(tx.Bucket(bucketName))
+		result, err := f(tr)
+		if err != nil {
+			return err
+		}
+		if tr.err != nil {
+			return tr.err
+		}
+		err = tr.writeVersionstamped()
+		if err != nil {
+			return err
+		}
+		ret = result
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (d *Database) ReadTransact(f func(tr storage.ReadTransaction) (interface{}, error)) (interface{}, error) {
+	var ret interface{}
+	err := d.db.View(func(tx *bolt.Tx) error {
+		tr := newTransaction(tx.Bucket(bucketName))
+		result, err := f(tr)
+		if err != nil {
+			return err
+		}
+		ret = result
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+type versionstampedWrite struct {
+	key     []byte
+	value   []byte
+	offset  int
+	inValue bool
+}
+
+type transaction struct {
+	bucket *bolt.Bucket
+	// original values of modified keys, used to implement Reset
+	undo map[string][]byte
+	// versionstamped writes are applied only when the transaction function succeeds
+	versionstamped []*versionstampedWrite
+	// err is the first failed write, Transact returns it and bolt rolls the transaction back
+	err error
+}
+
+func newTransaction(bucket *bolt.Bucket) *transaction {
+	return &transaction{bucket: bucket, undo: make(map[string][]byte)}
+}
+
+func (t *transaction) Get(key []byte) storage.FutureValue {
+	return &future{value: copyBytes(t.bucket.Get(key))}
+}
+
+func (t *transaction) GetRange(r storage.KeyRange, options storage.RangeOptions) ([]storage.KeyValue, error) {
+	toReturn := []storage.KeyValue{}
+	c := t.bucket.Cursor()
+	var k, v []byte
+	if options.Reverse {
+		k, v = c.Seek(r.End)
+		if k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+	} else {
+		k, v = c.Seek(r.Begin)
+	}
+	for k != nil {
+		if bytes.Compare(k, r.Begin) < 0 || bytes.Compare(k, r.End) >= 0 {
+			break
+		}
+		toReturn = append(toReturn, storage.KeyValue{Key: copyBytes(k), Value: copyBytes(v)})
+		if options.Limit > 0 && len(toReturn) == options.Limit {
+			break
+		}
+		if options.Reverse {
+			k, v = c.Prev()
+		} else {
+			k, v = c.Next()
+		}
+	}
+	return toReturn, nil
+}
+
+// Snapshot returns the transaction itself, bolt transactions are already isolated
+func (t *transaction) Snapshot() storage.ReadTransaction {
+	return t
+}
+
+func (t *transaction) Set(key []byte, value []byte) {
+	t.remember(key)
+	t.fail(t.bucket.Put(copyBytes(key), copyBytes(value)))
+}
+
+func (t *transaction) SetVersionstampedKey(key []byte, offset int, value []byte) {
+	if !t.bucket.Tx().Writable() {
+		panic(errors.New("Write in a read only transaction"))
+	}
+	if offset < 0 || offset+storage.VersionstampLength > len(key) {
+		panic(errors.New("Versionstamp offset is out of the key"))
+	}
+	t.versionstamped = append(t.versionstamped, &versionstampedWrite{key: copyBytes(key), offset: offset, value: copyBytes(value)})
+}
+
+func (t *transaction) SetVersionstampedValue(key []byte, value []byte, offset int) {
+	if !t.bucket.Tx().Writable() {
+		panic(errors.New("Write in a read only transaction"))
+	}
+	if offset < 0 || offset+storage.VersionstampLength > len(value) {
+		panic(errors.New("Versionstamp offset is out of the value"))
+	}
+	t.versionstamped = append(t.versionstamped, &versionstampedWrite{key: copyBytes(key), value: copyBytes(value), offset: offset, inValue: true})
+}
+
+// writeVersionstamped uses the persisted bucket sequence as a commit version
+func (t *transaction) writeVersionstamped() error {
+	if len(t.versionstamped) == 0 {
+		return nil
+	}
+	version, err := t.bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+	stamp := make([]byte, storage.VersionstampLength)
+	binary.BigEndian.PutUint64(stamp, version)
+	for _, write := range t.versionstamped {
+		if write.inValue {
+			copy(write.value[write.offset:], stamp)
+		} else {
+			copy(write.key[write.offset:], stamp)
+		}
+		err = t.bucket.Put(write.key, write.value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *transaction) Clear(key []byte) {
+	t.remember(key)
+	t.fail(t.bucket.Delete(key))
+}
+
+func (t *transaction) Reset() {
+	for key, value := range t.undo {
+		var err error
+		if value == nil {
+			err = t.bucket.Delete([]byte(key))
+		} else {
+			err = t.bucket.Put([]byte(key), value)
+		}
+		t.fail(err)
+	}
+	t.undo = make(map[string][]byte)
+	t.versionstamped = nil
+}
+
+// fail keeps the first error, Reset does not clear it
+func (t *transaction) fail(err error) {
+	if err != nil && t.err == nil {
+		t.err = err
+	}
+}
+
+func (t *transaction) remember(key []byte) {
+	if !t.bucket.Tx().Writable() {
+		panic(errors.New("Write in a read only transaction"))
+	}
+	if _, ok := t.undo[string(key)]; ok {
+		return
+	}
+	t.undo[string(key)] = copyBytes(t.bucket.Get(key))
+}
+
+type future struct {
+	value []byte
+}
+
+func (f *future) Get() ([]byte, error) {
+	return f.value, nil
+}
+
+func (f *future) MustGet() []byte {
+	return f.value
+}
+
+func copyBytes(value []byte) []byte {
+	if value == nil {
+		return nil
+	}
+	return append([]byte{}, value...)
+}
@@ -0,0 +1,163 @@
+package boltstorage
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+)
+
+func openTestDatabase(t *testing.T) (*Database, func()) {
+	dir, err := ioutil.TempDir("", "boltstorage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open(filepath.Join(dir, "plasma.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestResetAndRangeReads(t *testing.T) {
+	db, cleanup := openTestDatabase(t)
+	defer cleanup()
+	_, err := db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		tr.Set([]byte("p1"), []byte{1})
+		tr.Reset()
+		tr.Set([]byte("p2"), []byte{2})
+		tr.Set([]byte("p3"), []byte{3})
+		tr.Set([]byte("q1"), []byte{4})
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := storage.PrefixRange([]byte("p"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return tr.GetRange(pr, storage.RangeOptions{Limit: 1, Reverse: true})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := ret.([]storage.KeyValue)
+	if len(values) != 1 || string(values[0].Key) != "p3" {
+		t.Fatal("Reverse range read returned unexpected keys")
+	}
+	ret, err = db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return tr.GetRange(pr, storage.RangeOptions{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values = ret.([]storage.KeyValue)
+	if len(values) != 2 || string(values[0].Key) != "p2" {
+		t.Fatal("Reset did not discard a write")
+	}
+}
+
+func TestConcurrentSpendingOfSameKey(t *testing.T) {
+	db, cleanup := openTestDatabase(t)
+	defer cleanup()
+	utxo := []byte("utxo")
+	_, err := db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		tr.Set(utxo, []byte{1})
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	results := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := db.Transact(func(tr storage.Transaction) (interface{}, error) {
+				if bytes.Compare(tr.Get(utxo).MustGet(), []byte{1}) != 0 {
+					return nil, errors.New("Double spend")
+				}
+				tr.Clear(utxo)
+				return nil, nil
+			})
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+	spent := 0
+	for err := range results {
+		if err == nil {
+			spent++
+		}
+	}
+	if spent != 1 {
+		t.Fatal("UTXO should be spent exactly once")
+	}
+}
+
+func TestVersionstampedKeysFollowCommitOrder(t *testing.T) {
+	db, cleanup := openTestDatabase(t)
+	defer cleanup()
+	for i := 0; i < 3; i++ {
+		_, err := db.Transact(func(tr storage.Transaction) (interface{}, error) {
+			key := append([]byte("v"), make([]byte, storage.VersionstampLength)...)
+			tr.SetVersionstampedKey(key, 1, []byte{byte(i)})
+			if i == 1 {
+				tr.Reset()
+			}
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	pr, err := storage.PrefixRange([]byte("v"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return tr.GetRange(pr, storage.RangeOptions{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := ret.([]storage.KeyValue)
+	if len(values) != 2 || values[0].Value[0] != 0 || values[1].Value[0] != 2 {
+		t.Fatal("Versionstamped keys are not in commit order")
+	}
+}
+
+func TestFailedWriteRollsBackTransaction(t *testing.T) {
+	db, cleanup := openTestDatabase(t)
+	defer cleanup()
+	_, err := db.Transact(func(tr storage.Transaction) (interface{}, error) {
+		tr.Set([]byte("w1"), []byte{1})
+		tr.Set([]byte{}, []byte{2})
+		return nil, nil
+	})
+	if err == nil {
+		t.Fatal("Write with an empty key should fail the transaction")
+	}
+	ret, err := db.ReadTransact(func(tr storage.ReadTransaction) (interface{}, error) {
+		return tr.Get([]byte("w1")).Get()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.([]byte) != nil {
+		t.Fatal("Writes of a failed transaction should not be committed")
+	}
+}
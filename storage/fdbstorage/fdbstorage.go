@@ -0,0 +1,135 @@
+package fdbstorage
+
+import (
+	"encoding/binary"
+
+	fdb "github.com/apple/foundationdb/bindings/go/src/fdb"
+	"github.com/matterinc/PlasmaBlockCreator/storage"
+)
+
+// Database implements storage.Database on top of a FoundationDB cluster
+type Database struct {
+	db fdb.Database
+}
+
+func NewDatabase(db fdb.Database) *Database {
+	database := &Database{db: db}
+	return database
+}
+
+func (d *Database) Transact(f func(tr storage.Transaction) (interface{}, error)) (interface{}, error) {
+	return d.db.Transact(func(tr fdb.Transaction) (interface{}, error) {
+		return f(newTransaction(tr))
+	})
+}
+
+func (d *Database) ReadTransact(f func(tr storage.ReadTransaction) (interface{}, error)) (interface{}, error) {
+	return d.db.ReadTransact(func(tr fdb.ReadTransaction) (interface{}, error) {
+		return f(&readTransaction{tr})
+	})
+}
+
+func (d *Database) GetReadVersion() (int64, error) {
+	ret, err := d.db.ReadTransact(func(tr fdb.ReadTransaction) (interface{}, error) {
+		return tr.GetReadVersion().Get()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ret.(int64), nil
+}
+
+// ReadTransactAt does not retry, a retry at the same version would fail the same way
+func (d *Database) ReadTransactAt(version int64, f func(tr storage.ReadTransaction) (interface{}, error)) (ret interface{}, err error) {
+	tr, err := d.db.CreateTransaction()
+	if err != nil {
+		return nil, err
+	}
+	// futures panic with fdb.Error, as inside Transact
+	defer func() {
+		if r := recover(); r != nil {
+			fdbErr, ok := r.(fdb.Error)
+			if !ok {
+				panic(r)
+			}
+			ret, err = nil, fdbErr
+		}
+	}()
+	tr.SetReadVersion(version)
+	return f(&readTransaction{tr})
+}
+
+type readTransaction struct {
+	tr fdb.ReadTransaction
+}
+
+func (t *readTransaction) Get(key []byte) storage.FutureValue {
+	return t.tr.Get(fdb.Key(key))
+}
+
+func (t *readTransaction) GetRange(r storage.KeyRange, options storage.RangeOptions) ([]storage.KeyValue, error) {
+	kr := fdb.KeyRange{Begin: fdb.Key(r.Begin), End: fdb.Key(r.End)}
+	fdbOptions := fdb.RangeOptions{Limit: options.Limit, Reverse: options.Reverse, Mode: streamingMode(options.Mode)}
+	values, err := t.tr.GetRange(kr, fdbOptions).GetSliceWithError()
+	if err != nil {
+		return nil, err
+	}
+	toReturn := make([]storage.KeyValue, len(values))
+	for i, kv := range values {
+		toReturn[i] = storage.KeyValue{Key: kv.Key, Value: kv.Value}
+	}
+	return toReturn, nil
+}
+
+func (t *readTransaction) Snapshot() storage.ReadTransaction {
+	return &readTransaction{t.tr.Snapshot()}
+}
+
+type transaction struct {
+	readTransaction
+	tr fdb.Transaction
+}
+
+func newTransaction(tr fdb.Transaction) *transaction {
+	return &transaction{readTransaction{tr}, tr}
+}
+
+func (t *transaction) Set(key []byte, value []byte) {
+	t.tr.Set(fdb.Key(key), value)
+}
+
+// SetVersionstampedKey appends the offset as a little endian uint32, as expected by API version 520 and above
+func (t *transaction) SetVersionstampedKey(key []byte, offset int, value []byte) {
+	param := make([]byte, len(key)+4)
+	copy(param, key)
+	binary.LittleEndian.PutUint32(param[len(key):], uint32(offset))
+	t.tr.SetVersionstampedKey(fdb.Key(param), value)
+}
+
+func (t *transaction) SetVersionstampedValue(key []byte, value []byte, offset int) {
+	param := make([]byte, len(value)+4)
+	copy(param, value)
+	binary.LittleEndian.PutUint32(param[len(value):], uint32(offset))
+	t.tr.SetVersionstampedValue(fdb.Key(key), param)
+}
+
+func (t *transaction) Clear(key []byte) {
+	t.tr.Clear(fdb.Key(key))
+}
+
+func (t *transaction) Reset() {
+	t.tr.Reset()
+}
+
+func streamingMode(mode storage.StreamingMode) fdb.StreamingMode {
+	switch mode {
+	case storage.StreamingModeWantAll:
+		return fdb.StreamingMode(fdb.StreamingModeWantAll)
+	case storage.StreamingModeSmall:
+		return fdb.StreamingMode(fdb.StreamingModeSmall)
+	case storage.StreamingModeSerial:
+		return fdb.StreamingMode(fdb.StreamingModeSerial)
+	default:
+		return fdb.StreamingMode(fdb.StreamingModeIterator)
+	}
+}
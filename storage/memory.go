@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// MemoryDatabase is an in-process implementation of Database. Transactions are
+// executed one at a time under a single lock, so they are trivially serializable.
+// Writes are buffered and only applied if the transaction function returns no error
+type MemoryDatabase struct {
+	lock sync.RWMutex
+	keys []string
+	data map[string][]byte
+	// number of committed transactions that have used versionstamps
+	version uint64
+}
+
+func NewMemoryDatabase() *MemoryDatabase {
+	db := &MemoryDatabase{keys: []string{}, data: make(map[string][]byte)}
+	return db
+}
+
+func (d *MemoryDatabase) Transact(f func(tr Transaction) (interface{}, error)) (interface{}, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	tr := newMemoryTransaction(d)
+	ret, err := f(tr)
+	if err != nil {
+		return nil, err
+	}
+	d.apply(tr.writes)
+	if len(tr.versionstamped) != 0 {
+		d.version++
+		stamp := make([]byte, VersionstampLength)
+		binary.BigEndian.PutUint64(stamp, d.version)
+		for _, write := range tr.versionstamped {
+			key := copyBytes(write.key)
+			value := copyBytes(write.value)
+			if write.inValue {
+				copy(value[write.offset:], stamp)
+			} else {
+				copy(key[write.offset:], stamp)
+			}
+			d.applyOne(string(key), &memoryWrite{value: value})
+		}
+	}
+	return ret, nil
+}
+
+func (d *MemoryDatabase) ReadTransact(f func(tr ReadTransaction) (interface{}, error)) (interface{}, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	tr := newMemoryTransaction(d)
+	return f(tr)
+}
+
+func (d *MemoryDatabase) apply(writes map[string]*memoryWrite) {
+	for key, write := range writes {
+		d.applyOne(key, write)
+	}
+}
+
+func (d *MemoryDatabase) applyOne(key string, write *memoryWrite) {
+	i := sort.SearchStrings(d.keys, key)
+	exists := i < len(d.keys) && d.keys[i] == key
+	if write.cleared {
+		if exists {
+			d.keys = append(d.keys[:i], d.keys[i+1:]...)
+			delete(d.data, key)
+		}
+		return
+	}
+	if !exists {
+		d.keys = append(d.keys, "")
+		copy(d.keys[i+1:], d.keys[i:])
+		d.keys[i] = key
+	}
+	d.data[key] = write.value
+}
+
+type memoryWrite struct {
+	value   []byte
+	cleared bool
+}
+
+type memoryVersionstampedWrite struct {
+	key     []byte
+	value   []byte
+	offset  int
+	inValue bool
+}
+
+type memoryTransaction struct {
+	db             *MemoryDatabase
+	writes         map[string]*memoryWrite
+	versionstamped []*memoryVersionstampedWrite
+}
+
+func newMemoryTransaction(db *MemoryDatabase) *memoryTransaction {
+	return &memoryTransaction{db: db, writes: make(map[string]*memoryWrite)}
+}
+
+func (t *memoryTransaction) Get(key []byte) FutureValue {
+	k := string(key)
+	if write, ok := t.writes[k]; ok {
+		if write.cleared {
+			return &memoryFuture{}
+		}
+		return &memoryFuture{value: copyBytes(write.value)}
+	}
+	value, ok := t.db.data[k]
+	if !ok {
+		return &memoryFuture{}
+	}
+	return &memoryFuture{value: copyBytes(value)}
+}
+
+func (t *memoryTransaction) GetRange(r KeyRange, options RangeOptions) ([]KeyValue, error) {
+	begin := string(r.Begin)
+	end := string(r.End)
+	candidates := []string{}
+	from := sort.SearchStrings(t.db.keys, begin)
+	for i := from; i < len(t.db.keys) && t.db.keys[i] < end; i++ {
+		if _, ok := t.writes[t.db.keys[i]]; ok {
+			continue
+		}
+		candidates = append(candidates, t.db.keys[i])
+	}
+	for key, write := range t.writes {
+		if write.cleared || key < begin || key >= end {
+			continue
+		}
+		candidates = append(candidates, key)
+	}
+	sort.Strings(candidates)
+	if options.Reverse {
+		for i, j := 0, len(candidates)-1; i < j; i, j = i+1, j-1 {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		}
+	}
+	if options.Limit > 0 && len(candidates) > options.Limit {
+		candidates = candidates[:options.Limit]
+	}
+	toReturn := make([]KeyValue, len(candidates))
+	for i, key := range candidates {
+		toReturn[i] = KeyValue{Key: []byte(key), Value: t.Get([]byte(key)).MustGet()}
+	}
+	return toReturn, nil
+}
+
+// Snapshot returns the transaction itself, reads are already isolated by the database lock
+func (t *memoryTransaction) Snapshot() ReadTransaction {
+	return t
+}
+
+func (t *memoryTransaction) Set(key []byte, value []byte) {
+	t.writes[string(key)] = &memoryWrite{value: copyBytes(value)}
+}
+
+func (t *memoryTransaction) SetVersionstampedKey(key []byte, offset int, value []byte) {
+	if offset < 0 || offset+VersionstampLength > len(key) {
+		panic(errors.New("Versionstamp offset is out of the key"))
+	}
+	t.versionstamped = append(t.versionstamped, &memoryVersionstampedWrite{key: copyBytes(key), offset: offset, value: copyBytes(value)})
+}
+
+func (t *memoryTransaction) SetVersionstampedValue(key []byte, value []byte, offset int) {
+	if offset < 0 || offset+VersionstampLength > len(value) {
+		panic(errors.New("Versionstamp offset is out of the value"))
+	}
+	t.versionstamped = append(t.versionstamped, &memoryVersionstampedWrite{key: copyBytes(key), value: copyBytes(value), offset: offset, inValue: true})
+}
+
+func (t *memoryTransaction) Clear(key []byte) {
+	t.writes[string(key)] = &memoryWrite{cleared: true}
+}
+
+func (t *memoryTransaction) Reset() {
+	t.writes = make(map[string]*memoryWrite)
+	t.versionstamped = nil
+}
+
+type memoryFuture struct {
+	value []byte
+}
+
+func (f *memoryFuture) Get() ([]byte, error) {
+	return f.value, nil
+}
+
+func (f *memoryFuture) MustGet() []byte {
+	return f.value
+}
+
+func copyBytes(value []byte) []byte {
+	if value == nil {
+		return nil
+	}
+	return append([]byte{}, value...)
+}
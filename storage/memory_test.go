@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestMemoryDatabaseCommitsAndRollsBack(t *testing.T) {
+	db := NewMemoryDatabase()
+	_, err := db.Transact(func(tr Transaction) (interface{}, error) {
+		tr.Set([]byte("a"), []byte{1})
+		tr.Set([]byte("b"), []byte{2})
+		if bytes.Compare(tr.Get([]byte("a")).MustGet(), []byte{1}) != 0 {
+			return nil, errors.New("Transaction should read its own writes")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Transact(func(tr Transaction) (interface{}, error) {
+		tr.Clear([]byte("a"))
+		tr.Set([]byte("c"), []byte{3})
+		return nil, errors.New("Abort")
+	})
+	if err == nil {
+		t.Fatal("Expected an error from aborted transaction")
+	}
+	ret, err := db.ReadTransact(func(tr ReadTransaction) (interface{}, error) {
+		return tr.GetRange(KeyRange{Begin: []byte("a"), End: []byte("z")}, RangeOptions{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := ret.([]KeyValue)
+	if len(values) != 2 || string(values[0].Key) != "a" || string(values[1].Key) != "b" {
+		t.Fatal("Aborted transaction has modified the database")
+	}
+}
+
+func TestMemoryDatabaseRangeReads(t *testing.T) {
+	db := NewMemoryDatabase()
+	_, err := db.Transact(func(tr Transaction) (interface{}, error) {
+		for _, key := range []string{"p1", "p3", "q1", "p2"} {
+			tr.Set([]byte(key), []byte(key))
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := PrefixRange([]byte("p"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, err := db.Transact(func(tr Transaction) (interface{}, error) {
+		tr.Clear([]byte("p3"))
+		tr.Set([]byte("p0"), []byte("p0"))
+		return tr.GetRange(pr, RangeOptions{Limit: 2, Reverse: true})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := ret.([]KeyValue)
+	if len(values) != 2 || string(values[0].Key) != "p2" || string(values[1].Key) != "p1" {
+		t.Fatal("Reverse range read with limit returned unexpected keys")
+	}
+	ret, err = db.ReadTransact(func(tr ReadTransaction) (interface{}, error) {
+		return tr.Snapshot().GetRange(pr, RangeOptions{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values = ret.([]KeyValue)
+	if len(values) != 3 || string(values[0].Key) != "p0" || string(values[2].Key) != "p2" {
+		t.Fatal("Range read returned unexpected keys")
+	}
+}
+
+func TestMemoryDatabaseVersionstampedKeys(t *testing.T) {
+	db := NewMemoryDatabase()
+	for i := 0; i < 2; i++ {
+		_, err := db.Transact(func(tr Transaction) (interface{}, error) {
+			key := append([]byte("v"), make([]byte, VersionstampLength)...)
+			tr.SetVersionstampedKey(key, 1, []byte{byte(i)})
+			tr.SetVersionstampedValue([]byte{'w', byte(i)}, key, 1)
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	pr, err := PrefixRange([]byte("v"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, err := db.ReadTransact(func(tr ReadTransaction) (interface{}, error) {
+		return tr.GetRange(pr, RangeOptions{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := ret.([]KeyValue)
+	if len(values) != 2 || values[0].Value[0] != 0 || values[1].Value[0] != 1 {
+		t.Fatal("Versionstamped keys are not in commit order")
+	}
+	pointer := db.data[string([]byte{'w', 1})]
+	if bytes.Compare(pointer, values[1].Key) != 0 {
+		t.Fatal("Versionstamped value does not match the key of the same transaction")
+	}
+}
+
+// pinnedDatabase checks that every page is read at the version taken first
+type pinnedDatabase struct {
+	*MemoryDatabase
+	version int64
+	reads   int
+}
+
+func (d *pinnedDatabase) GetReadVersion() (int64, error) {
+	d.version++
+	return d.version, nil
+}
+
+func (d *pinnedDatabase) ReadTransactAt(version int64, f func(tr ReadTransaction) (interface{}, error)) (interface{}, error) {
+	if version != 1 {
+		return nil, errors.New("Page was read at another version")
+	}
+	d.reads++
+	return d.ReadTransact(f)
+}
+
+func TestReadPagesSplitsRanges(t *testing.T) {
+	db := NewMemoryDatabase()
+	_, err := db.Transact(func(tr Transaction) (interface{}, error) {
+		for i := 0; i < 25; i++ {
+			tr.Set([]byte{'k', byte(i)}, []byte{byte(i)})
+		}
+		tr.Set([]byte("l"), []byte{0})
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ := PrefixRange([]byte("k"))
+	versioned := &pinnedDatabase{MemoryDatabase: db}
+	for _, database := range []Database{db, versioned} {
+		pages := []int{}
+		next := 0
+		err = ReadPages(database, r, 10, func(page []KeyValue) error {
+			for _, kv := range page {
+				if kv.Value[0] != byte(next) {
+					return errors.New("Records are out of order")
+				}
+				next++
+			}
+			pages = append(pages, len(page))
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pages) != 3 || pages[0] != 10 || pages[2] != 5 {
+			t.Fatal("Expected pages of 10, 10 and 5 records", pages)
+		}
+	}
+	if versioned.reads != 3 {
+		t.Fatal("Every page should be read in a transaction of its own")
+	}
+	pages := 0
+	err = ReadPages(db, r, 10, func(page []KeyValue) error {
+		pages++
+		return ErrStopPages
+	})
+	if err != nil || pages != 1 {
+		t.Fatal("Reading should stop without an error")
+	}
+}
@@ -0,0 +1,160 @@
+package storage
+
+import "errors"
+
+// KeyValue is a single record returned from a range read
+type KeyValue struct {
+	Key   []byte
+	Value []byte
+}
+
+// KeyRange is a half-open [Begin, End) range of keys
+type KeyRange struct {
+	Begin []byte
+	End   []byte
+}
+
+// StreamingMode is a hint for the backend on how eagerly to fetch range reads
+type StreamingMode int
+
+const (
+	StreamingModeIterator StreamingMode = iota
+	StreamingModeWantAll
+	StreamingModeSmall
+	StreamingModeSerial
+)
+
+type RangeOptions struct {
+	Limit   int
+	Reverse bool
+	Mode    StreamingMode
+}
+
+// FutureValue is a result of a point read that may be resolved later,
+// so a transaction can issue several reads before waiting on any of them
+type FutureValue interface {
+	Get() ([]byte, error)
+	MustGet() []byte
+}
+
+type ReadTransaction interface {
+	Get(key []byte) FutureValue
+	GetRange(r KeyRange, options RangeOptions) ([]KeyValue, error)
+	Snapshot() ReadTransaction
+}
+
+// VersionstampLength is the length of a versionstamp, 8 bytes of a commit version
+// followed by 2 bytes of an order inside the commit, both big endian
+const VersionstampLength = 10
+
+type Transaction interface {
+	ReadTransaction
+	Set(key []byte, value []byte)
+	// SetVersionstampedKey writes a value under a key whose VersionstampLength bytes
+	// starting from offset are replaced on commit with a versionstamp of the transaction.
+	// Versionstamps are unique and grow with the commit order. The key is not visible
+	// to reads of the same transaction
+	SetVersionstampedKey(key []byte, offset int, value []byte)
+	// SetVersionstampedValue is the same for a value, used to point at a versionstamped key
+	SetVersionstampedValue(key []byte, value []byte, offset int)
+	Clear(key []byte)
+	Reset()
+}
+
+// Database is a transactional ordered key-value store. Transact must provide
+// serializable isolation, UTXOWriter relies on it to resolve double spends
+type Database interface {
+	Transact(func(tr Transaction) (interface{}, error)) (interface{}, error)
+	ReadTransact(func(tr ReadTransaction) (interface{}, error)) (interface{}, error)
+}
+
+// VersionedDatabase can run several read transactions at one read version, so a long read
+// can be split into transactions that still see a single state of the database
+type VersionedDatabase interface {
+	Database
+	// GetReadVersion returns the version a new read transaction would read at
+	GetReadVersion() (int64, error)
+	// ReadTransactAt runs f once at the given version. Old versions are not kept forever,
+	// FoundationDB fails reads older than 5 seconds
+	ReadTransactAt(version int64, f func(tr ReadTransaction) (interface{}, error)) (interface{}, error)
+}
+
+// ErrStopPages returned by the function passed to ReadPages stops reading without an error
+var ErrStopPages = errors.New("Stop reading pages")
+
+// ReadPages reads a range in pages of at most pageSize records and passes every page to f.
+// A VersionedDatabase reads every page in a transaction of its own at the same read version,
+// other databases read all pages in one transaction
+func ReadPages(db Database, r KeyRange, pageSize int, f func(page []KeyValue) error) error {
+	versioned, ok := db.(VersionedDatabase)
+	if !ok {
+		_, err := db.ReadTransact(func(tr ReadTransaction) (interface{}, error) {
+			return nil, readPages(r, pageSize, f, func(pageRange KeyRange, options RangeOptions) ([]KeyValue, error) {
+				return tr.GetRange(pageRange, options)
+			})
+		})
+		return err
+	}
+	version, err := versioned.GetReadVersion()
+	if err != nil {
+		return err
+	}
+	return readPages(r, pageSize, f, func(pageRange KeyRange, options RangeOptions) ([]KeyValue, error) {
+		ret, err := versioned.ReadTransactAt(version, func(tr ReadTransaction) (interface{}, error) {
+			return tr.GetRange(pageRange, options)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return ret.([]KeyValue), nil
+	})
+}
+
+func readPages(r KeyRange, pageSize int, f func(page []KeyValue) error, getRange func(KeyRange, RangeOptions) ([]KeyValue, error)) error {
+	options := RangeOptions{Limit: pageSize, Mode: StreamingModeWantAll}
+	pageRange := r
+	for {
+		page, err := getRange(pageRange, options)
+		if err != nil {
+			return err
+		}
+		if len(page) != 0 {
+			err = f(page)
+			if err == ErrStopPages {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if len(page) < pageSize {
+			return nil
+		}
+		// the next page starts right after the last key
+		lastKey := page[len(page)-1].Key
+		pageRange = KeyRange{Begin: append(append([]byte{}, lastKey...), 0x00), End: r.End}
+	}
+}
+
+// PrefixRange returns a range of all keys that start with a given prefix
+func PrefixRange(prefix []byte) (KeyRange, error) {
+	end, err := strinc(prefix)
+	if err != nil {
+		return KeyRange{}, err
+	}
+	begin := make([]byte, len(prefix))
+	copy(begin, prefix)
+	return KeyRange{Begin: begin, End: end}, nil
+}
+
+func strinc(prefix []byte) ([]byte, error) {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] != 0xff {
+			end[i]++
+			return end[:i+1], nil
+		}
+	}
+	return nil, errors.New("Prefix must contain at least one byte not equal to 0xff")
+}